@@ -0,0 +1,41 @@
+// Command search-index maintains the article search index snapshot of the
+// app. It reads conf/app.conf like the app, so run it from the project root:
+//
+//	go run ./cmd/search-index rebuild
+//
+// The app reloads the snapshot on its next start, a running app keeps its own
+// index and overwrites the snapshot when it saves.
+package main
+
+import (
+	searchRepo "article-app/internal/data/search/repository"
+	"article-app/pkg/database"
+	"article-app/pkg/search"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+func main() {
+	if len(os.Args) != 2 || os.Args[1] != "rebuild" {
+		fmt.Fprintln(os.Stderr, "usage: search-index rebuild")
+		os.Exit(2)
+	}
+
+	searchIndexPath := beego.AppConfig.DefaultString("searchIndexPath", "data/search.idx")
+	languages := strings.Split(beego.AppConfig.DefaultString("lang", "en|id"), "|")
+
+	index := search.NewIndex(search.Analyzer{Languages: search.LookupLanguages(languages...)})
+	repository := searchRepo.NewSearchRepository(database.DB(), index, searchIndexPath)
+	if err := repository.Rebuild(context.Background()); err != nil {
+		log.Fatalln("error rebuilding search index:", err)
+	}
+	if err := repository.Flush(); err != nil {
+		log.Fatalln("error saving search index:", err)
+	}
+	log.Printf("indexed %d articles into %s\n", index.Len(), searchIndexPath)
+}
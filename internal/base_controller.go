@@ -2,8 +2,11 @@ package internal
 
 import (
 	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
 	"article-app/pkg/helper"
+	"article-app/pkg/jwt"
 	"article-app/pkg/response"
+	"errors"
 	"net/http"
 
 	beego "github.com/beego/beego/v2/server/web"
@@ -43,6 +46,54 @@ func (r *BaseController) SetLangVersion() {
 	r.Lang = lang
 }
 
+// ResponseSortError answers an invalid sort query param, the allowed fields are
+// listed in the response data so clients can correct the request.
+func (c *BaseController) ResponseSortError(err error) {
+	var sortErr *paginator.SortError
+	if errors.As(err, &sortErr) {
+		c.ResponseErrorWithData(c.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, c.Lang), err, sortErr)
+		return
+	}
+	c.ResponseError(c.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, c.Lang), err)
+}
+
+// ResponseUseCaseError answers an error returned by a usecase with the status
+// and code domain.ErrorStatus maps it to, unknown errors are a 500.
+func (c *BaseController) ResponseUseCaseError(err error) {
+	code := ""
+	switch {
+	case jwt.IsIdentityMissing(err):
+		code = domain.UnauthorizedCodeError
+	case jwt.IsInvalidToken(err):
+		code = domain.InvalidTokenCodeError
+	case jwt.IsExpiredToken(err):
+		code = domain.ExpiredTokenCodeError
+	case jwt.IsAuthElsewhere(err):
+		code = domain.AuthElseWhereCodeError
+	}
+	if code != "" {
+		c.ResponseError(c.Ctx, http.StatusUnauthorized, code, domain.ErrorCodeText(code, c.Lang), err)
+		return
+	}
+	status, code := domain.ErrorStatus(err)
+	c.ResponseError(c.Ctx, status, code, domain.ErrorCodeText(code, c.Lang), err)
+}
+
+// ValidateRequest checks request with Validate. An invalid request is
+// answered with 422 listing the invalid fields and false is returned.
+func (c *BaseController) ValidateRequest(request interface{}) bool {
+	errs, err := Validate(c.Lang, request)
+	if err != nil {
+		c.ResponseError(c.Ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, c.Lang), err)
+		return false
+	}
+	if len(errs) > 0 {
+		c.ResponseValidationError(c.Ctx, http.StatusUnprocessableEntity, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, c.Lang), errs)
+		return false
+	}
+	return true
+}
+
 func (c *BaseController) Error404() {
 	c.ResponseError(c.Ctx, http.StatusNotFound, domain.ResourceNotFoundCodeError, domain.ErrorCodeText(domain.ResourceNotFoundCodeError, helper.GetLangVersion(c.Ctx)), nil)
 	return
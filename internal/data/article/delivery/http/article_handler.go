@@ -3,13 +3,19 @@ package http
 import (
 	"article-app/internal"
 	"article-app/internal/domain"
+	"article-app/internal/middlewares"
 	"article-app/pkg/database/paginator"
+	"article-app/pkg/helper"
 	"article-app/pkg/jwt"
 	"article-app/pkg/response"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
 )
 
 type articleHandler struct {
@@ -17,18 +23,48 @@ type articleHandler struct {
 	response.ApiResponse
 	ArticleUseCase domain.ArticleUseCase
 	JwtAuth        jwt.JWT
+	RequireIfMatch bool
 }
 
-func NewArticleHandler(useCase domain.ArticleUseCase, jwt jwt.JWT) {
+func NewArticleHandler(useCase domain.ArticleUseCase, jwt jwt.JWT, rbac *middlewares.RbacConfig, requireIfMatch bool) {
 	pHandler := &articleHandler{
 		ArticleUseCase: useCase,
 		JwtAuth:        jwt,
+		RequireIfMatch: requireIfMatch,
 	}
 	beego.Router("/api/v1/cms/article", pHandler, "post:CreateArticle")
 	beego.Router("/api/v1/cms/article", pHandler, "get:GetArticles")
+	beego.Router("/api/v1/cms/article/scheduled", pHandler, "get:GetScheduledArticles")
+	beego.Router("/api/v1/cms/article/slug/:slug", pHandler, "get:GetArticleBySlug")
 	beego.Router("/api/v1/cms/article/:id", pHandler, "get:GetArticleById")
 	beego.Router("/api/v1/cms/article/:id", pHandler, "patch:UpdateArticle")
 	beego.Router("/api/v1/cms/article/:id", pHandler, "delete:DeleteArticle")
+	beego.Router("/api/v1/cms/article/:id/submit", pHandler, "post:SubmitArticle")
+	beego.Router("/api/v1/cms/article/:id/publish", pHandler, "post:PublishArticle")
+	beego.Router("/api/v1/cms/article/:id/archive", pHandler, "post:ArchiveArticle")
+	beego.Router("/api/v1/cms/article/:id/schedule", pHandler, "post:ScheduleArticle")
+	beego.Router("/api/v1/cms/article/:id/revisions", pHandler, "get:GetArticleRevisions")
+	beego.Router("/api/v1/cms/article/:id/revisions/diff", pHandler, "get:GetArticleRevisionDiff")
+	beego.Router("/api/v1/cms/article/:id/revisions/:rev/restore", pHandler, "post:RestoreArticleRevision")
+
+	// public, only published articles are served
+	beego.Router("/api/v1/article/:slug", pHandler, "get:GetPublishedArticleBySlug")
+
+	// required permissions
+	beego.InsertFilterChain("/api/v1/cms/article", rbac.Require(http.MethodPost, domain.PermissionArticleCreate))
+	beego.InsertFilterChain("/api/v1/cms/article", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/article/scheduled", rbac.Require(http.MethodGet, domain.PermissionArticlePublish))
+	beego.InsertFilterChain("/api/v1/cms/article/slug/:slug", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/article/:id", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/article/:id", rbac.Require(http.MethodPatch, domain.PermissionArticleUpdate))
+	beego.InsertFilterChain("/api/v1/cms/article/:id", rbac.Require(http.MethodDelete, domain.PermissionArticleDelete))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/submit", rbac.Require(http.MethodPost, domain.PermissionArticleUpdate))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/publish", rbac.Require(http.MethodPost, domain.PermissionArticlePublish))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/archive", rbac.Require(http.MethodPost, domain.PermissionArticlePublish))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/schedule", rbac.Require(http.MethodPost, domain.PermissionArticlePublish))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/revisions", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/revisions/diff", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/revisions/:rev/restore", rbac.Require(http.MethodPost, domain.PermissionArticleUpdate))
 }
 
 func (h *articleHandler) Prepare() {
@@ -42,16 +78,48 @@ func (h *articleHandler) CreateArticle() {
 		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
 		return
 	}
+	if !h.ValidateRequest(request) {
+		return
+	}
 
 	data, err := h.ArticleUseCase.CreateArticle(h.Ctx, request)
 	if err != nil {
-		h.ResponseError(h.Ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, h.Locale.Lang), err)
+		if errors.Is(err, domain.ErrInvalidTag) || errors.Is(err, domain.ErrInvalidCategory) {
+			h.responseInvalidReference(err)
+			return
+		}
+		h.ResponseUseCaseError(err)
 		return
 	}
 	h.Ok(h.Ctx, h.Tr("message.success"), data)
 	return
 }
 
+// GetArticles
+// @Title GetArticles
+// @Summary List articles, by page or by cursor when the cursor param is sent
+// @Produce json
+// @Tags Article
+// @Success 200 {object} swagger.ListResponse{data=[]domain.GetArticleResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total records"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Param cursor query string false "cursor of the cursor pagination, the pagination then carries next_cursor and prev_cursor"
+// @Param with_total query bool false "count the total in cursor pagination"
+// @Param sort query string false "e.g. -created_at,title, or -relevance when searching"
+// @Param search query string false "full text search in title and body, ranked by relevance unless sorted otherwise"
+// @Param search_mode query string false "natural (default) or boolean, which reads the operators + - * and quotes"
+// @Param status query string false "article status"
+// @Param author query int false "author id"
+// @Param tag query int false "tag id"
+// @Param category query int false "category id"
+// @Security ApiKeyAuth
+// @Router /v1/cms/article [get]
 func (h *articleHandler) GetArticles() {
 	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
 	if err != nil {
@@ -59,11 +127,70 @@ func (h *articleHandler) GetArticles() {
 		return
 	}
 
+	// a cursor param, even an empty one, switches the list to keyset pagination
+	_, cursorMode := h.Ctx.Request.URL.Query()["cursor"]
+	search := strings.TrimSpace(h.Ctx.Input.Query("search"))
+	sortSpec := domain.ArticleSort
+	if cursorMode {
+		sortSpec = domain.ArticleCursorSort
+	} else if search != "" {
+		// a search is ranked by relevance
+		sortSpec = domain.ArticleSearchSort
+	}
+
+	sort, err := domain.SortQueryParamValidation(sortSpec, h.Ctx.Input.Query("sort"), h.Ctx.Input.Query("sort_by"), h.Ctx.Input.Query("order_by"))
+	if err != nil {
+		h.ResponseSortError(err)
+		return
+	}
+
 	filter := domain.GetArticlesFilter{
-		OrderBy: h.Ctx.Input.Query("order_by"),
-		SortBy:  h.Ctx.Input.Query("sort_by"),
-		Search:  h.Ctx.Input.Query("search"),
-		Author:  h.Ctx.Input.Query("author"),
+		Sort:       sort,
+		Search:     search,
+		SearchMode: h.Ctx.Input.Query("search_mode"),
+		Status:     h.Ctx.Input.Query("status"),
+	}
+
+	if filter.SearchMode != "" && !domain.IsValidArticleSearchMode(filter.SearchMode) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+		return
+	}
+
+	if filter.Status != "" && !domain.IsValidArticleStatus(filter.Status) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+		return
+	}
+
+	if author := h.Ctx.Input.Query("author"); author != "" {
+		authorID, err := strconv.Atoi(author)
+		if err != nil || authorID < 1 {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+			return
+		}
+		filter.AuthorID = authorID
+	}
+
+	if tag := h.Ctx.Input.Query("tag"); tag != "" {
+		tagID, err := strconv.Atoi(tag)
+		if err != nil || tagID < 1 {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+			return
+		}
+		filter.TagID = tagID
+	}
+
+	if category := h.Ctx.Input.Query("category"); category != "" {
+		categoryID, err := strconv.Atoi(category)
+		if err != nil || categoryID < 1 {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+			return
+		}
+		filter.CategoryID = categoryID
+	}
+
+	if cursorMode {
+		h.getArticlesByCursor(pageSize, filter)
+		return
 	}
 
 	limit, page, offset := paginator.Pagination(page, pageSize)
@@ -71,10 +198,69 @@ func (h *articleHandler) GetArticles() {
 	result, err := h.ArticleUseCase.GetArticles(h.Ctx, page, limit, offset, filter)
 
 	if err != nil {
-		h.ResponseError(h.Ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, h.Locale.Lang), err)
+		h.ResponseUseCaseError(err)
 		return
 	}
-	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
+
+	return
+}
+
+func (h *articleHandler) getArticlesByCursor(pageSize int, filter domain.GetArticlesFilter) {
+	withTotal := false
+	if v := h.Ctx.Input.Query("with_total"); v != "" {
+		var err error
+		if withTotal, err = strconv.ParseBool(v); err != nil {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+			return
+		}
+	}
+
+	result, err := h.ArticleUseCase.GetArticlesByCursor(h.Ctx, h.Ctx.Input.Query("cursor"), pageSize, withTotal, filter)
+	if err != nil {
+		if errors.Is(err, paginator.ErrCursorInvalid) {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+			return
+		}
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.CursorPagination(result))
+
+	return
+}
+
+// GetScheduledArticles
+// @Title GetScheduledArticles
+// @Summary List the articles waiting for their publish_at
+// @Produce json
+// @Tags Article
+// @Success 200 {object} swagger.ListResponse{data=[]domain.GetArticleResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total records"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Security ApiKeyAuth
+// @Router /v1/cms/article/scheduled [get]
+func (h *articleHandler) GetScheduledArticles() {
+	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	limit, page, offset := paginator.Pagination(page, pageSize)
+
+	result, err := h.ArticleUseCase.GetScheduledArticles(h.Ctx, page, limit, offset)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
 
 	return
 }
@@ -88,7 +274,38 @@ func (h *articleHandler) GetArticleById() {
 
 	result, err := h.ArticleUseCase.GetArticleById(h.Ctx, pathParam)
 	if err != nil {
-		h.ResponseError(h.Ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, h.Locale.Lang), err)
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ctx.Output.Header("ETag", helper.FormatETag(result.Version))
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+
+	return
+}
+
+func (h *articleHandler) GetArticleBySlug() {
+	result, err := h.ArticleUseCase.GetArticleBySlug(h.Ctx, h.Ctx.Input.Param(":slug"))
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ctx.Output.Header("ETag", helper.FormatETag(result.Version))
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+
+	return
+}
+
+// GetPublishedArticleBySlug answers retired slugs with a permanent redirect to the current one.
+func (h *articleHandler) GetPublishedArticleBySlug() {
+	slug := h.Ctx.Input.Param(":slug")
+	result, err := h.ArticleUseCase.GetPublishedArticleBySlug(h.Ctx, slug)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+
+	if result.Slug != slug {
+		h.Ctx.Redirect(http.StatusMovedPermanently, "/api/v1/article/"+url.PathEscape(result.Slug))
 		return
 	}
 	h.Ok(h.Ctx, h.Tr("message.success"), result)
@@ -103,6 +320,11 @@ func (h *articleHandler) UpdateArticle() {
 		return
 	}
 
+	version, ok := h.ifMatch()
+	if !ok {
+		return
+	}
+
 	var request domain.UpdateArticleRequest
 	h.BindJSON(&request)
 
@@ -110,12 +332,20 @@ func (h *articleHandler) UpdateArticle() {
 		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
 		return
 	}
+	if !h.ValidateRequest(request) {
+		return
+	}
 
-	data, err := h.ArticleUseCase.UpdateArticle(h.Ctx, request, pathParam)
+	data, err := h.ArticleUseCase.UpdateArticle(h.Ctx, request, pathParam, version)
 	if err != nil {
-		h.ResponseError(h.Ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, h.Locale.Lang), err)
+		if errors.Is(err, domain.ErrInvalidTag) || errors.Is(err, domain.ErrInvalidCategory) {
+			h.responseInvalidReference(err)
+			return
+		}
+		h.ResponseUseCaseError(err)
 		return
 	}
+	h.Ctx.Output.Header("ETag", helper.FormatETag(data.Version))
 	h.Ok(h.Ctx, h.Tr("message.success"), data)
 	return
 }
@@ -127,11 +357,185 @@ func (h *articleHandler) DeleteArticle() {
 		return
 	}
 
-	h.ArticleUseCase.DeleteArticle(h.Ctx, pathParam)
+	version, ok := h.ifMatch()
+	if !ok {
+		return
+	}
+
+	err = h.ArticleUseCase.DeleteArticle(h.Ctx, pathParam, version)
 	if err != nil {
-		h.ResponseError(h.Ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, h.Locale.Lang), err)
+		h.ResponseUseCaseError(err)
 		return
 	}
 	h.Ok(h.Ctx, h.Tr("message.success"), nil)
 	return
 }
+
+func (h *articleHandler) SubmitArticle() {
+	h.transition(h.ArticleUseCase.SubmitArticle)
+}
+
+func (h *articleHandler) PublishArticle() {
+	h.transition(h.ArticleUseCase.PublishArticle)
+}
+
+func (h *articleHandler) ArchiveArticle() {
+	h.transition(h.ArticleUseCase.ArchiveArticle)
+}
+
+func (h *articleHandler) ScheduleArticle() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.ScheduleArticleRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	data, err := h.ArticleUseCase.ScheduleArticle(h.Ctx, request, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+// GetArticleRevisions
+// @Title GetArticleRevisions
+// @Summary List the revisions of an article, newest first
+// @Produce json
+// @Tags Article Revision
+// @Success 200 {object} swagger.ListResponse{data=[]domain.ArticleRevisionResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total records"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param id path int true "article id"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Security ApiKeyAuth
+// @Router /v1/cms/article/{id}/revisions [get]
+func (h *articleHandler) GetArticleRevisions() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	limit, page, _ := paginator.Pagination(page, pageSize)
+
+	result, err := h.ArticleUseCase.GetArticleRevisions(h.Ctx, pathParam, page, limit)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
+	return
+}
+
+func (h *articleHandler) GetArticleRevisionDiff() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	from, err := strconv.Atoi(h.Ctx.Input.Query("from"))
+	if err != nil || from < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+		return
+	}
+
+	to, err := strconv.Atoi(h.Ctx.Input.Query("to"))
+	if err != nil || to < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+		return
+	}
+
+	data, err := h.ArticleUseCase.GetArticleRevisionDiff(h.Ctx, pathParam, from, to)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+func (h *articleHandler) RestoreArticleRevision() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	revision, err := strconv.Atoi(h.Ctx.Input.Param(":rev"))
+	if err != nil || revision < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	data, err := h.ArticleUseCase.RestoreArticleRevision(h.Ctx, pathParam, revision)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+// ifMatch returns the article version from the If-Match header, 0 when absent.
+// The error response is written when it returns false.
+func (h *articleHandler) ifMatch() (int, bool) {
+	header := h.Ctx.Input.Header("If-Match")
+	if header == "" && h.RequireIfMatch {
+		h.ResponseError(h.Ctx, http.StatusPreconditionRequired, domain.PreconditionRequiredCode, domain.ErrorCodeText(domain.PreconditionRequiredCode, h.Locale.Lang), domain.ErrPreconditionRequired)
+		return 0, false
+	}
+
+	version, err := helper.ParseIfMatch(header)
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusPreconditionFailed, domain.PreconditionFailedCode, domain.ErrorCodeText(domain.PreconditionFailedCode, h.Locale.Lang), err)
+		return 0, false
+	}
+	return version, true
+}
+
+// transition runs one of the article workflow use cases for the :id path param.
+func (h *articleHandler) transition(useCase func(beegoCtx *beegoContext.Context, id int) (*domain.GetArticleResponse, error)) {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	data, err := useCase(h.Ctx, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+// responseInvalidReference answers tag or category ids that are not registered
+// as an invalid field.
+func (h *articleHandler) responseInvalidReference(err error) {
+	field, message := "tag_ids", h.Tr("validation.unknownTag")
+	if errors.Is(err, domain.ErrInvalidCategory) {
+		field, message = "category_ids", h.Tr("validation.unknownCategory")
+	}
+	h.ResponseValidationError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), []response.Errors{{Field: field, Description: message}})
+}
@@ -2,20 +2,25 @@ package repository
 
 import (
 	"article-app/internal/domain"
+	"article-app/pkg/database"
 	"article-app/pkg/database/paginator"
 	"context"
-	"strings"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ArticleRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	searcher domain.ArticleSearcher
 }
 
-func NewArticleRepository(db *gorm.DB) domain.ArticleRepository {
+func NewArticleRepository(db *gorm.DB, searcher domain.ArticleSearcher) domain.ArticleRepository {
 	return &ArticleRepository{
-		db: db,
+		db:       db,
+		searcher: searcher,
 	}
 }
 
@@ -23,43 +28,205 @@ func (ar ArticleRepository) DB() *gorm.DB {
 	return ar.db
 }
 
+// Store returns domain.ErrConflict when the slug was taken since it was checked.
 func (ar ArticleRepository) Store(ctx context.Context, tx *gorm.DB, data domain.Article) (int, error) {
 	err := tx.WithContext(ctx).Create(&data).Error
 	if err != nil {
-		return 0, err
+		return 0, slugError(err)
 	}
 	return data.ID, nil
 }
 
-func (ar ArticleRepository) FetchWithFilterAndPagination(ctx context.Context, page, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (*paginator.Paginator, error) {
-	p := paginator.NewPaginator(ar.db, page, limit, model)
-	if err := p.FindWithFilter(ctx, order, fields, associate, filter, args...).Select(strings.Join(fields, ",")).Error; err != nil {
-		return p, err
+func (ar ArticleRepository) FetchWithFilterAndPagination(ctx context.Context, page, limit int, offset int, order string, fields, associate []string, filter paginator.Criteria, search *domain.ArticleSearch) (*paginator.Page[domain.Article], error) {
+	db, fields, err := ar.listQuery(ctx, fields, search)
+	if err != nil {
+		return nil, err
+	}
+	return paginator.NewPaginator[domain.Article](db, page, limit).FindWithFilter(ctx, order, fields, associate, filter)
+}
+
+func (ar ArticleRepository) FetchWithFilterAndCursor(ctx context.Context, cursor string, limit int, sort paginator.Sort, withTotal bool, fields, associate []string, filter paginator.Criteria, search *domain.ArticleSearch) (*paginator.CursorPage[domain.Article], error) {
+	db, fields, err := ar.listQuery(ctx, fields, search)
+	if err != nil {
+		return nil, err
 	}
-	return p, nil
+	return paginator.NewCursorPaginator[domain.Article](db, limit).FindWithFilter(ctx, cursor, sort, withTotal, fields, associate, filter)
+}
+
+// listQuery prepares the article list, a search joins the matches of the
+// searcher and selects their relevance into Score.
+func (ar ArticleRepository) listQuery(ctx context.Context, fields []string, search *domain.ArticleSearch) (*gorm.DB, []string, error) {
+	db := ar.db.Preload("Tags").Preload("Categories")
+	if search != nil {
+		join, args, err := ar.searcher.Join(ctx, *search)
+		if err != nil {
+			return nil, nil, err
+		}
+		db = db.Joins(join, args...)
+		fields = append(fields[:len(fields):len(fields)], "search.score AS score")
+	}
+	return db.Session(&gorm.Session{}), fields, nil
 }
 
 func (ar ArticleRepository) FindByID(ctx context.Context, id int) (*domain.Article, error) {
 	var entity domain.Article
-	err := ar.db.WithContext(ctx).First(&entity, "id =?", id).Error
+	err := ar.db.WithContext(ctx).Preload("Author").Preload("Tags").Preload("Categories").First(&entity, "id =?", id).Error
 	if err != nil {
-		return nil, err
+		return nil, domain.RepositoryError(err)
 	}
 	return &entity, nil
 }
 
-func (ar ArticleRepository) Update(ctx context.Context, data domain.Article, id int) error {
-	err := ar.db.WithContext(ctx).Where("articles.id = ?", id).Updates(&data).Error
-	if err != nil {
+// Update only applies when the stored version still equals version and bumps it,
+// otherwise domain.ErrVersionMismatch is returned. domain.ErrConflict is
+// returned when the new slug was taken since it was checked.
+func (ar ArticleRepository) Update(ctx context.Context, tx *gorm.DB, data domain.Article, id, version int) error {
+	data.Version = version + 1
+	result := tx.WithContext(ctx).Omit(clause.Associations).Where("articles.id = ? AND articles.version = ?", id, version).Updates(&data)
+	if result.Error != nil {
+		return slugError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrVersionMismatch
+	}
+	return nil
+}
+
+// FindBySlug looks the article up by its current slug and falls back to the
+// slugs it used before.
+func (ar ArticleRepository) FindBySlug(ctx context.Context, slug string) (*domain.Article, error) {
+	var entity domain.Article
+	err := ar.db.WithContext(ctx).Preload("Author").Preload("Tags").Preload("Categories").First(&entity, "slug =?", slug).Error
+	if err == nil {
+		return &entity, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.RepositoryError(err)
+	}
+
+	var retired domain.ArticleSlug
+	if err = ar.db.WithContext(ctx).First(&retired, "slug =?", slug).Error; err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return ar.FindByID(ctx, retired.ArticleID)
+}
+
+// SlugTaken reports whether slug is used, now or in the past, by an article other than articleID.
+func (ar ArticleRepository) SlugTaken(ctx context.Context, tx *gorm.DB, slug string, articleID int) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).Unscoped().Model(&domain.Article{}).Where("slug = ? AND id <> ?", slug, articleID).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = tx.WithContext(ctx).Model(&domain.ArticleSlug{}).Where("slug = ? AND article_id <> ?", slug, articleID).Count(&count).Error
+	return count > 0, err
+}
+
+// RetireSlug keeps oldSlug as a redirect to the article. newSlug is released
+// in case the article used it before and takes it back.
+func (ar ArticleRepository) RetireSlug(ctx context.Context, tx *gorm.DB, articleID int, oldSlug, newSlug string) error {
+	err := tx.WithContext(ctx).Exec("delete from article_slugs where article_id =? and slug =?", articleID, newSlug).Error
+	if err != nil || oldSlug == "" {
 		return err
 	}
+	return slugError(tx.WithContext(ctx).Create(&domain.ArticleSlug{ArticleID: articleID, Slug: oldSlug}).Error)
+}
+
+// slugError maps the violation of a unique slug to domain.ErrConflict, another
+// request took the slug between SlugTaken and the write.
+func slugError(err error) error {
+	if database.IsDuplicateKey(err) {
+		return domain.ErrConflict
+	}
+	return err
+}
+
+// Delete removes the article together with its tag and category assignments
+// and its slugs. It only applies when the stored version still equals version,
+// otherwise domain.ErrVersionMismatch is returned and nothing is removed.
+func (ar ArticleRepository) Delete(ctx context.Context, id, version int) error {
+	return ar.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("delete from article_tags where article_id =?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("delete from article_categories where article_id =?", id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec("delete from articles where id =? and version =?", id, version)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrVersionMismatch
+		}
+		return tx.Exec("delete from article_slugs where article_id =?", id).Error
+	})
+}
+
+// UpdateStatus only applies when the stored version still equals version and
+// bumps it, otherwise domain.ErrVersionMismatch is returned.
+func (ar ArticleRepository) UpdateStatus(ctx context.Context, id, version int, status string, publishedAt *time.Time) error {
+	return ar.updateVersioned(ctx, id, version, map[string]interface{}{
+		"status":       status,
+		"published_at": publishedAt,
+	})
+}
+
+// UpdateSchedule only applies when the stored version still equals version and
+// bumps it, otherwise domain.ErrVersionMismatch is returned.
+func (ar ArticleRepository) UpdateSchedule(ctx context.Context, id, version int, publishAt *time.Time) error {
+	return ar.updateVersioned(ctx, id, version, map[string]interface{}{
+		"publish_at": publishAt,
+	})
+}
+
+func (ar ArticleRepository) updateVersioned(ctx context.Context, id, version int, values map[string]interface{}) error {
+	values["version"] = version + 1
+	result := ar.db.WithContext(ctx).Model(&domain.Article{}).Where("articles.id = ? AND articles.version = ?", id, version).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrVersionMismatch
+	}
 	return nil
 }
 
-func (ar ArticleRepository) Delete(ctx context.Context, id int) error {
-	err := ar.db.WithContext(ctx).Exec("delete from articles where id =?", id).Error
+// ClaimScheduled locks the articles in review whose publish_at is due.
+// Rows already locked by another instance are skipped, so concurrent
+// schedulers never promote the same article twice.
+func (ar ArticleRepository) ClaimScheduled(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]domain.Article, error) {
+	var entities []domain.Article
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("articles.status = ? AND articles.publish_at <= ?", domain.ArticleStatusInReview, now).
+		Order("articles.publish_at").
+		Limit(limit).
+		Find(&entities).Error
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (ar ArticleRepository) PublishScheduled(ctx context.Context, tx *gorm.DB, ids []int) error {
+	err := tx.WithContext(ctx).Model(&domain.Article{}).Where("articles.id IN ?", ids).Updates(map[string]interface{}{
+		"status":       domain.ArticleStatusPublished,
+		"published_at": gorm.Expr("articles.publish_at"),
+		"version":      gorm.Expr("version + 1"),
+	}).Error
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (ar ArticleRepository) ReplaceTags(ctx context.Context, tx *gorm.DB, article *domain.Article, tags []domain.Tag) error {
+	return tx.WithContext(ctx).Model(article).Association("Tags").Replace(tags)
+}
+
+func (ar ArticleRepository) ReplaceCategories(ctx context.Context, tx *gorm.DB, article *domain.Article, categories []domain.Category) error {
+	return tx.WithContext(ctx).Model(article).Association("Categories").Replace(categories)
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens an in memory database enforcing the foreign keys, like
+// MySQL does.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(&domain.User{}, &domain.Tag{}, &domain.Category{}, &domain.Article{}, &domain.ArticleSlug{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createTaggedArticle(t *testing.T, db *gorm.DB) domain.Article {
+	t.Helper()
+	author := domain.User{Email: "author@mail.com", Password: "Password123"}
+	if err := db.Create(&author).Error; err != nil {
+		t.Fatal(err)
+	}
+	article := domain.Article{
+		AuthorID:   author.Id,
+		Title:      "Go generics",
+		Slug:       "go-generics",
+		Body:       "body",
+		Status:     domain.ArticleStatusDraft,
+		Version:    1,
+		Tags:       []domain.Tag{{Name: "go"}},
+		Categories: []domain.Category{{Name: "programming"}},
+	}
+	if err := db.Create(&article).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&domain.ArticleSlug{ArticleID: article.ID, Slug: "generics"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return article
+}
+
+func countRows(t *testing.T, db *gorm.DB, table string, articleID int) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Table(table).Where("article_id = ?", articleID).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestArticleRepositoryDeleteTaggedArticle(t *testing.T) {
+	db := openTestDB(t)
+	article := createTaggedArticle(t, db)
+	repo := NewArticleRepository(db, nil)
+
+	if err := repo.Delete(context.Background(), article.ID, article.Version); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	var articles int64
+	db.Unscoped().Model(&domain.Article{}).Where("id = ?", article.ID).Count(&articles)
+	if articles != 0 {
+		t.Errorf("article still stored")
+	}
+	for _, table := range []string{"article_tags", "article_categories", "article_slugs"} {
+		if n := countRows(t, db, table, article.ID); n != 0 {
+			t.Errorf("%s has %d rows left", table, n)
+		}
+	}
+
+	var tags, categories int64
+	db.Model(&domain.Tag{}).Count(&tags)
+	db.Model(&domain.Category{}).Count(&categories)
+	if tags != 1 || categories != 1 {
+		t.Errorf("tags = %d, categories = %d, want the tag and the category kept", tags, categories)
+	}
+}
+
+func TestArticleRepositoryDeleteVersionMismatch(t *testing.T) {
+	db := openTestDB(t)
+	article := createTaggedArticle(t, db)
+	repo := NewArticleRepository(db, nil)
+
+	err := repo.Delete(context.Background(), article.ID, article.Version+1)
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("Delete() error = %v, want %v", err, domain.ErrVersionMismatch)
+	}
+	for _, table := range []string{"article_tags", "article_categories", "article_slugs"} {
+		if n := countRows(t, db, table, article.ID); n != 1 {
+			t.Errorf("%s has %d rows, want the assignment kept", table, n)
+		}
+	}
+}
+
+// A slug taken between SlugTaken and the write, by a concurrent request, is
+// reported as a conflict for the use case to pick another one.
+func TestArticleRepositoryTakenSlugConflicts(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	article := createTaggedArticle(t, db)
+	repo := NewArticleRepository(db, nil)
+
+	_, err := repo.Store(ctx, db, domain.Article{AuthorID: article.AuthorID, Title: "Go generics", Slug: article.Slug, Body: "body", Version: 1})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Store() error = %v, want ErrConflict", err)
+	}
+
+	other, err := repo.Store(ctx, db, domain.Article{AuthorID: article.AuthorID, Title: "Rust", Slug: "rust", Body: "body", Version: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Update(ctx, db, domain.Article{Slug: article.Slug}, other, 1)
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Update() error = %v, want ErrConflict", err)
+	}
+	err = repo.RetireSlug(ctx, db, other, "generics", "rust-2")
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("RetireSlug() error = %v, want ErrConflict", err)
+	}
+}
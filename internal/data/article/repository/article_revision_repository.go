@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArticleRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleRevisionRepository(db *gorm.DB) domain.ArticleRevisionRepository {
+	return &ArticleRevisionRepository{
+		db: db,
+	}
+}
+
+func (rr ArticleRevisionRepository) Store(ctx context.Context, tx *gorm.DB, data domain.ArticleRevision) (int, error) {
+	err := tx.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return 0, err
+	}
+	return data.Revision, nil
+}
+
+// NextRevision returns the next revision number of the article. The article row
+// is locked so concurrent edits of the same article are numbered one after another.
+func (rr ArticleRevisionRepository) NextRevision(ctx context.Context, tx *gorm.DB, articleID int) (int, error) {
+	var lock domain.Article
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&lock, "id =?", articleID).Error
+	if err != nil {
+		return 0, domain.RepositoryError(err)
+	}
+
+	var last int
+	err = tx.WithContext(ctx).Model(&domain.ArticleRevision{}).Where("article_id = ?", articleID).Select("COALESCE(MAX(revision), 0)").Scan(&last).Error
+	if err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+func (rr ArticleRevisionRepository) FindByRevision(ctx context.Context, articleID, revision int) (*domain.ArticleRevision, error) {
+	var entity domain.ArticleRevision
+	err := rr.db.WithContext(ctx).Preload("Editor").First(&entity, "article_id = ? AND revision = ?", articleID, revision).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+func (rr ArticleRevisionRepository) FetchByArticle(ctx context.Context, articleID, page, limit int) (*paginator.Page[domain.ArticleRevision], error) {
+	p := paginator.NewPaginator[domain.ArticleRevision](rr.db, page, limit)
+	criteria := paginator.Eq("article_revisions.article_id", articleID)
+	return p.FindWithFilter(ctx, "article_revisions.revision desc", nil, []string{"Editor"}, criteria)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/search"
+	"context"
+	"strings"
+)
+
+// localSearchLimit caps the matches of the embedded index, only the most
+// relevant ones are listed.
+const localSearchLimit = 1000
+
+// MySQLArticleSearcher searches the FULLTEXT index on articles (title, body).
+type MySQLArticleSearcher struct{}
+
+func NewMySQLArticleSearcher() domain.ArticleSearcher {
+	return MySQLArticleSearcher{}
+}
+
+func (MySQLArticleSearcher) Join(ctx context.Context, query domain.ArticleSearch) (string, []interface{}, error) {
+	match := "MATCH (title, body) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	if query.Mode == domain.ArticleSearchBoolean {
+		match = "MATCH (title, body) AGAINST (? IN BOOLEAN MODE)"
+	}
+	join := "JOIN (SELECT id AS article_id, " + match + " AS score FROM articles WHERE " + match + ") AS search ON search.article_id = articles.id"
+	return join, []interface{}{query.Text, query.Text}, nil
+}
+
+// LocalArticleSearcher searches the article index embedded in the process,
+// for the databases without a full text index such as SQLite. The index is
+// shared with the search repository, which keeps it up to date.
+type LocalArticleSearcher struct {
+	index *search.Index
+}
+
+func NewLocalArticleSearcher(index *search.Index) domain.ArticleSearcher {
+	return &LocalArticleSearcher{
+		index: index,
+	}
+}
+
+// Join ranks the matches with a CASE over their ids, which every SQL dialect understands.
+func (s *LocalArticleSearcher) Join(ctx context.Context, query domain.ArticleSearch) (string, []interface{}, error) {
+	var hits []search.Hit
+	if q := search.ParseQuery(query.Text, query.Mode == domain.ArticleSearchBoolean); !q.IsEmpty() {
+		hits = s.index.Search(q, search.Options{Limit: localSearchLimit}).Hits
+	}
+	if len(hits) == 0 {
+		return "JOIN (SELECT id AS article_id, 0 AS score FROM articles WHERE 1 = 0) AS search ON search.article_id = articles.id", nil, nil
+	}
+
+	var score strings.Builder
+	args := make([]interface{}, 0, len(hits)*2+1)
+	ids := make([]int, len(hits))
+	for k, v := range hits {
+		score.WriteString(" WHEN ? THEN ?")
+		args = append(args, v.ID, v.Score)
+		ids[k] = v.ID
+	}
+	args = append(args, ids)
+
+	join := "JOIN (SELECT id AS article_id, CASE id" + score.String() + " END AS score FROM articles WHERE id IN ?) AS search ON search.article_id = articles.id"
+	return join, args, nil
+}
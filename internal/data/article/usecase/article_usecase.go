@@ -3,26 +3,49 @@ package usecase
 import (
 	"article-app/internal/domain"
 	"article-app/pkg/database/paginator"
+	"article-app/pkg/diff"
+	"article-app/pkg/helper"
 	"article-app/pkg/jwt"
+	"article-app/pkg/search"
+	"article-app/pkg/slug"
 	"context"
-	"fmt"
+	"errors"
+	"log"
+	"strings"
 	"time"
 
 	beegoContext "github.com/beego/beego/v2/server/web/context"
 	"gorm.io/gorm"
 )
 
+const (
+	// scheduledBatchSize caps how many due articles one scheduler tick claims.
+	scheduledBatchSize = 100
+	// snippetSize is the length in characters of the body snippet of a search result.
+	snippetSize = 200
+	// slugAttempts bounds the writes retried when a concurrent one took their slug.
+	slugAttempts = 3
+)
+
 type articleUseCase struct {
 	contextTimeout time.Duration
 	articleRepo    domain.ArticleRepository
+	revisionRepo   domain.ArticleRevisionRepository
+	tagRepo        domain.TagRepository
+	categoryRepo   domain.CategoryRepository
+	searchRepo     domain.SearchRepository
 	jwtAuth        jwt.JWT
 	expireToken    int
 }
 
-func NewArticleUseCase(timeout time.Duration, ur domain.ArticleRepository, jwtAuth jwt.JWT, expireToken int) domain.ArticleUseCase {
+func NewArticleUseCase(timeout time.Duration, ur domain.ArticleRepository, rr domain.ArticleRevisionRepository, tr domain.TagRepository, cr domain.CategoryRepository, sr domain.SearchRepository, jwtAuth jwt.JWT, expireToken int) domain.ArticleUseCase {
 	return &articleUseCase{
 		contextTimeout: timeout,
 		articleRepo:    ur,
+		revisionRepo:   rr,
+		tagRepo:        tr,
+		categoryRepo:   cr,
+		searchRepo:     sr,
 		jwtAuth:        jwtAuth,
 		expireToken:    expireToken,
 	}
@@ -33,61 +56,98 @@ func (auc articleUseCase) CreateArticle(beegoCtx *beegoContext.Context, body dom
 	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
 	defer cancel()
 
-	auc.articleRepo.DB().Transaction(func(tx *gorm.DB) error {
-		if articleId, err := auc.articleRepo.Store(ctx, tx, body.ToArticle()); err != nil {
+	authorID, err := helper.GetUserID(auc.jwtAuth, beegoCtx.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	article := body.ToArticle(authorID)
+	if article.Tags, err = auc.findTags(ctx, body.TagIDs); err != nil {
+		return nil, err
+	}
+	if article.Categories, err = auc.findCategories(ctx, body.CategoryIDs); err != nil {
+		return nil, err
+	}
+
+	err = retrySlugConflict(func() error {
+		return auc.articleRepo.DB().Transaction(func(tx *gorm.DB) error {
+			var err error
+			if article.Slug, err = auc.uniqueSlug(ctx, tx, article.Title, 0); err != nil {
+				return err
+			}
+
+			if articleId, err := auc.articleRepo.Store(ctx, tx, article); err != nil {
+				return err
+			} else {
+				//set returning id from db
+				id = articleId
+			}
+
+			// the first revision records the initial content
+			_, err = auc.revisionRepo.Store(ctx, tx, domain.ArticleRevision{
+				ArticleID: id,
+				Revision:  1,
+				EditorID:  authorID,
+				NewTitle:  article.Title,
+				NewBody:   article.Body,
+			})
 			return err
-		} else {
-			//set returning id from db
-			id = articleId
-		}
-		return nil
+		})
 	})
+	if err != nil {
+		return nil, err
+	}
+	auc.reindex(ctx, id)
+
 	data, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	res := data.ToArticleResponse()
-	return &res, err
+	return &res, nil
 }
 
-func (auc articleUseCase) GetArticles(beegoCtx *beegoContext.Context, page, limit, offset int, filter domain.GetArticlesFilter) (result *paginator.Paginator, err error) {
+func (auc articleUseCase) GetArticles(beegoCtx *beegoContext.Context, page, limit, offset int, filter domain.GetArticlesFilter) (result *paginator.Page[domain.GetArticleResponse], err error) {
 	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
 	defer cancel()
-	var entities []domain.Article
-
-	var where []string
-
-	if filter.Author != "" {
-		where = append(where, fmt.Sprintf("articles.author = '%s'", filter.Author))
-	}
-
-	if filter.Search != "" {
-		where = append(
-			where,
-			fmt.Sprintf(`articles.title like "%%%s%%" OR articles.body like "%%%s%%"`, filter.Search, filter.Search),
-		)
-	}
 
+	associate, criteria := articlesFilter(filter)
+	query := articleSearch(filter)
 	paging, err := auc.articleRepo.FetchWithFilterAndPagination(ctx,
 		page,
 		limit,
 		offset,
-		fmt.Sprintf("articles.%s %s", filter.SortBy, filter.OrderBy),
-		[]string{
-			"articles.id",
-			"articles.title",
-			"articles.author",
-			"articles.body",
-		}, nil, where, &entities, nil,
+		filter.Sort.OrderBy(),
+		articleListFields, associate, criteria, query,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	var dataList = make([]domain.GetArticleResponse, len(entities))
+	return paginator.MapPage(paging, auc.articleResponse(query)), nil
+}
 
-	for k, v := range entities {
-		dataList[k] = v.ToArticleResponse()
+// GetArticlesByCursor is the keyset variant of GetArticles, the total is only
+// counted when asked for.
+func (auc articleUseCase) GetArticlesByCursor(beegoCtx *beegoContext.Context, cursor string, limit int, withTotal bool, filter domain.GetArticlesFilter) (result *paginator.CursorPage[domain.GetArticleResponse], err error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	associate, criteria := articlesFilter(filter)
+	query := articleSearch(filter)
+	paging, err := auc.articleRepo.FetchWithFilterAndCursor(ctx,
+		cursor,
+		limit,
+		filter.Sort,
+		withTotal,
+		articleListFields, associate, criteria, query,
+	)
+	if err != nil {
+		return nil, err
 	}
-	paging.Records = dataList
-	return paging, nil
+
+	return paginator.MapCursorPage(paging, auc.articleResponse(query)), nil
 }
 
 func (auc articleUseCase) GetArticleById(beegoCtx *beegoContext.Context, id int) (*domain.GetArticleResponse, error) {
@@ -103,22 +163,80 @@ func (auc articleUseCase) GetArticleById(beegoCtx *beegoContext.Context, id int)
 	return &res, err
 }
 
-func (auc articleUseCase) UpdateArticle(beegoCtx *beegoContext.Context, body domain.UpdateArticleRequest, id int) (*domain.GetArticleResponse, error) {
+// GetArticleBySlug finds the article by its current or any retired slug.
+func (auc articleUseCase) GetArticleBySlug(beegoCtx *beegoContext.Context, slug string) (*domain.GetArticleResponse, error) {
 	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
 	defer cancel()
 
-	data := body.ToArticle()
-	err := auc.articleRepo.Update(ctx, data, id)
+	data, err := auc.articleRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	res := data.ToArticleResponse()
+	return &res, nil
+}
+
+// GetPublishedArticleBySlug is the public lookup, articles that are not
+// published are reported as domain.ErrNotFound. The response carries the
+// current slug, which differs from slug when a retired one was asked for.
+func (auc articleUseCase) GetPublishedArticleBySlug(beegoCtx *beegoContext.Context, slug string) (*domain.GetArticleResponse, error) {
+	res, err := auc.GetArticleBySlug(beegoCtx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status != domain.ArticleStatusPublished {
+		return nil, domain.ErrNotFound
+	}
+	return res, nil
+}
+
+// UpdateArticle applies the change only when the article is still at version,
+// a version of 0 means the caller sent no If-Match and the version just read is used.
+func (auc articleUseCase) UpdateArticle(beegoCtx *beegoContext.Context, body domain.UpdateArticleRequest, id, version int) (*domain.GetArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	found, err := auc.articleRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = auc.authorizeOwner(beegoCtx, found); err != nil {
+		return nil, err
+	}
+
+	if version > 0 && version != found.Version {
+		return nil, domain.ErrVersionMismatch
+	}
+
+	data := body.ToArticle()
+	if body.TagIDs != nil {
+		if data.Tags, err = auc.findTags(ctx, body.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+	if body.CategoryIDs != nil {
+		if data.Categories, err = auc.findCategories(ctx, body.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = auc.updateWithRevision(beegoCtx, ctx, found, data, nil); err != nil {
+		return nil, err
+	}
+
 	article, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	res := article.ToArticleResponse()
-	return &res, err
+	return &res, nil
 }
 
-func (auc articleUseCase) DeleteArticle(beegoCtx *beegoContext.Context, id int) error {
+func (auc articleUseCase) DeleteArticle(beegoCtx *beegoContext.Context, id, version int) error {
 	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
 	defer cancel()
 
@@ -128,10 +246,474 @@ func (auc articleUseCase) DeleteArticle(beegoCtx *beegoContext.Context, id int)
 	}
 
 	if found != nil {
-		err = auc.articleRepo.Delete(ctx, id)
+		if err = auc.authorizeOwner(beegoCtx, found); err != nil {
+			return err
+		}
+
+		if version > 0 && version != found.Version {
+			return domain.ErrVersionMismatch
+		}
+
+		err = auc.articleRepo.Delete(ctx, id, found.Version)
 		if err != nil {
 			return err
 		}
+		auc.reindex(ctx, id)
 	}
 	return nil
 }
+
+func (auc articleUseCase) SubmitArticle(beegoCtx *beegoContext.Context, id int) (*domain.GetArticleResponse, error) {
+	return auc.transition(beegoCtx, id, domain.ArticleStatusInReview, true)
+}
+
+func (auc articleUseCase) PublishArticle(beegoCtx *beegoContext.Context, id int) (*domain.GetArticleResponse, error) {
+	return auc.transition(beegoCtx, id, domain.ArticleStatusPublished, false)
+}
+
+func (auc articleUseCase) ArchiveArticle(beegoCtx *beegoContext.Context, id int) (*domain.GetArticleResponse, error) {
+	return auc.transition(beegoCtx, id, domain.ArticleStatusArchived, false)
+}
+
+// transition moves the article to status when the workflow allows it.
+// published_at is stamped the first time the article is published and kept afterwards.
+func (auc articleUseCase) transition(beegoCtx *beegoContext.Context, id int, status string, ownerOnly bool) (*domain.GetArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	found, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerOnly {
+		if err = auc.authorizeOwner(beegoCtx, found); err != nil {
+			return nil, err
+		}
+	}
+
+	if !found.CanTransitionTo(status) {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	publishedAt := found.PublishedAt
+	if status == domain.ArticleStatusPublished && publishedAt == nil {
+		now := time.Now()
+		publishedAt = &now
+	}
+
+	if err = auc.articleRepo.UpdateStatus(ctx, id, found.Version, status, publishedAt); err != nil {
+		return nil, err
+	}
+	auc.reindex(ctx, id)
+
+	article, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := article.ToArticleResponse()
+	return &res, nil
+}
+
+func (auc articleUseCase) ScheduleArticle(beegoCtx *beegoContext.Context, body domain.ScheduleArticleRequest, id int) (*domain.GetArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	if body.PublishAt != nil && !body.PublishAt.After(time.Now()) {
+		return nil, domain.ErrPublishAtInPast
+	}
+
+	found, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// only reviewed articles can be scheduled, the scheduler publishes them as is
+	if !found.CanTransitionTo(domain.ArticleStatusPublished) {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	if err = auc.articleRepo.UpdateSchedule(ctx, id, found.Version, body.PublishAt); err != nil {
+		return nil, err
+	}
+
+	article, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := article.ToArticleResponse()
+	return &res, nil
+}
+
+func (auc articleUseCase) GetScheduledArticles(beegoCtx *beegoContext.Context, page, limit, offset int) (result *paginator.Page[domain.GetArticleResponse], err error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	paging, err := auc.articleRepo.FetchWithFilterAndPagination(ctx,
+		page,
+		limit,
+		offset,
+		"articles.publish_at asc",
+		articleListFields, []string{"Author"}, paginator.And(
+			paginator.Eq("articles.status", domain.ArticleStatusInReview),
+			paginator.IsNotNull("articles.publish_at"),
+		), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginator.MapPage(paging, domain.Article.ToArticleResponse), nil
+}
+
+// PublishScheduledArticles promotes the articles whose publish_at is due and
+// returns how many were published. It is run by the background scheduler.
+func (auc articleUseCase) PublishScheduledArticles(ctx context.Context, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, auc.contextTimeout)
+	defer cancel()
+
+	var ids []int
+	err := auc.articleRepo.DB().Transaction(func(tx *gorm.DB) error {
+		due, err := auc.articleRepo.ClaimScheduled(ctx, tx, now, scheduledBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+
+		ids = make([]int, len(due))
+		for k, v := range due {
+			ids[k] = v.ID
+		}
+		return auc.articleRepo.PublishScheduled(ctx, tx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		auc.reindex(ctx, id)
+	}
+	return len(ids), nil
+}
+
+func (auc articleUseCase) GetArticleRevisions(beegoCtx *beegoContext.Context, id, page, limit int) (result *paginator.Page[domain.ArticleRevisionResponse], err error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	if _, err = auc.articleRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	paging, err := auc.revisionRepo.FetchByArticle(ctx, id, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return paginator.MapPage(paging, domain.ArticleRevision.ToArticleRevisionResponse), nil
+}
+
+func (auc articleUseCase) GetArticleRevisionDiff(beegoCtx *beegoContext.Context, id, from, to int) (*domain.ArticleRevisionDiffResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	fromRevision, err := auc.revisionRepo.FindByRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := auc.revisionRepo.FindByRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ArticleRevisionDiffResponse{
+		ArticleID: id,
+		From:      from,
+		To:        to,
+		Title:     diff.Lines(fromRevision.NewTitle, toRevision.NewTitle),
+		Body:      diff.Lines(fromRevision.NewBody, toRevision.NewBody),
+	}, nil
+}
+
+// RestoreArticleRevision puts back the content left by an old revision. History
+// is never rewritten, the restore itself is recorded as a new revision.
+func (auc articleUseCase) RestoreArticleRevision(beegoCtx *beegoContext.Context, id, revision int) (*domain.GetArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), auc.contextTimeout)
+	defer cancel()
+
+	found, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = auc.authorizeOwner(beegoCtx, found); err != nil {
+		return nil, err
+	}
+
+	old, err := auc.revisionRepo.FindByRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	data := domain.Article{Title: old.NewTitle, Body: old.NewBody}
+	if err = auc.updateWithRevision(beegoCtx, ctx, found, data, &old.Revision); err != nil {
+		return nil, err
+	}
+
+	article, err := auc.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := article.ToArticleResponse()
+	return &res, nil
+}
+
+// updateWithRevision applies data to the article and records the change as a
+// new revision in the same transaction. Nothing is recorded when the title and
+// body stay the same. Tags and categories are replaced only when data carries them.
+func (auc articleUseCase) updateWithRevision(beegoCtx *beegoContext.Context, ctx context.Context, found *domain.Article, data domain.Article, restoredFrom *int) error {
+	editorID, err := helper.GetUserID(auc.jwtAuth, beegoCtx.Request)
+	if err != nil {
+		return err
+	}
+
+	// empty fields are left untouched by the update
+	revision := domain.ArticleRevision{
+		ArticleID:    found.ID,
+		EditorID:     editorID,
+		OldTitle:     found.Title,
+		NewTitle:     found.Title,
+		OldBody:      found.Body,
+		NewBody:      found.Body,
+		RestoredFrom: restoredFrom,
+	}
+	if data.Title != "" {
+		revision.NewTitle = data.Title
+	}
+	if data.Body != "" {
+		revision.NewBody = data.Body
+	}
+
+	err = retrySlugConflict(func() error {
+		return auc.articleRepo.DB().Transaction(func(tx *gorm.DB) error {
+			// a new title moves the article to a new slug, the old one keeps redirecting
+			if revision.NewTitle != revision.OldTitle {
+				newSlug, err := auc.uniqueSlug(ctx, tx, revision.NewTitle, found.ID)
+				if err != nil {
+					return err
+				}
+				if newSlug != found.Slug {
+					if err = auc.articleRepo.RetireSlug(ctx, tx, found.ID, found.Slug, newSlug); err != nil {
+						return err
+					}
+					data.Slug = newSlug
+				}
+			}
+
+			if err := auc.articleRepo.Update(ctx, tx, data, found.ID, found.Version); err != nil {
+				return err
+			}
+
+			if data.Tags != nil {
+				if err := auc.articleRepo.ReplaceTags(ctx, tx, found, data.Tags); err != nil {
+					return err
+				}
+			}
+
+			if data.Categories != nil {
+				if err := auc.articleRepo.ReplaceCategories(ctx, tx, found, data.Categories); err != nil {
+					return err
+				}
+			}
+
+			if revision.OldTitle == revision.NewTitle && revision.OldBody == revision.NewBody {
+				return nil
+			}
+
+			next, err := auc.revisionRepo.NextRevision(ctx, tx, found.ID)
+			if err != nil {
+				return err
+			}
+			revision.Revision = next
+
+			_, err = auc.revisionRepo.Store(ctx, tx, revision)
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	auc.reindex(ctx, found.ID)
+	return nil
+}
+
+// reindex brings the search index up to date after a write, a deleted
+// article is removed from it. The article is already saved, so a failure is
+// only logged and fixed by a rebuild.
+func (auc articleUseCase) reindex(ctx context.Context, id int) {
+	article, err := auc.articleRepo.FindByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		err = auc.searchRepo.Remove(ctx, id)
+	} else if err == nil {
+		err = auc.searchRepo.Index(ctx, *article)
+	}
+	if err != nil {
+		log.Println("error indexing article", id, err)
+	}
+}
+
+// uniqueSlug derives a slug from title that no other article uses or used,
+// numeric suffixes are added until a free one is found.
+func (auc articleUseCase) uniqueSlug(ctx context.Context, tx *gorm.DB, title string, articleID int) (string, error) {
+	base := slug.Make(title)
+	if base == "" {
+		base = "article"
+	}
+
+	for n := 1; ; n++ {
+		candidate := slug.WithSuffix(base, n)
+		taken, err := auc.articleRepo.SlugTaken(ctx, tx, candidate, articleID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+}
+
+// retrySlugConflict runs the transaction fn again when another request took
+// its slug between uniqueSlug and the write, the next attempt sees that slug
+// taken. domain.ErrConflict is returned once the attempts run out.
+func retrySlugConflict(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < slugAttempts; attempt++ {
+		if err = fn(); !errors.Is(err, domain.ErrConflict) {
+			return err
+		}
+	}
+	return err
+}
+
+// findTags loads the tags with the given ids, domain.ErrInvalidTag is returned
+// when one of them does not exist. The result is never nil.
+func (auc articleUseCase) findTags(ctx context.Context, ids []int) ([]domain.Tag, error) {
+	tags, err := auc.tagRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(uniqueIDs(ids)) {
+		return nil, domain.ErrInvalidTag
+	}
+	return append([]domain.Tag{}, tags...), nil
+}
+
+// findCategories loads the categories with the given ids, domain.ErrInvalidCategory
+// is returned when one of them does not exist. The result is never nil.
+func (auc articleUseCase) findCategories(ctx context.Context, ids []int) ([]domain.Category, error) {
+	categories, err := auc.categoryRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) != len(uniqueIDs(ids)) {
+		return nil, domain.ErrInvalidCategory
+	}
+	return append([]domain.Category{}, categories...), nil
+}
+
+func uniqueIDs(ids []int) map[int]struct{} {
+	set := make(map[int]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+// articleListFields are the columns loaded for article lists. The cursor mode
+// reads the sort key back from the records, so every sortable column is listed.
+var articleListFields = []string{
+	"articles.id",
+	"articles.title",
+	"articles.slug",
+	"articles.author_id",
+	"articles.body",
+	"articles.status",
+	"articles.published_at",
+	"articles.publish_at",
+	"articles.version",
+	"articles.created_at",
+	"articles.updated_at",
+}
+
+// articlesFilter turns the list filter into the joins and criteria of the query.
+func articlesFilter(filter domain.GetArticlesFilter) ([]string, paginator.Criteria) {
+	var where []paginator.Criteria
+	associate := []string{"Author"}
+
+	if filter.AuthorID > 0 {
+		where = append(where, paginator.Eq("articles.author_id", filter.AuthorID))
+	}
+
+	if filter.Status != "" {
+		where = append(where, paginator.Eq("articles.status", filter.Status))
+	}
+
+	if filter.TagID > 0 {
+		associate = append(associate, "JOIN article_tags ON article_tags.article_id = articles.id")
+		where = append(where, paginator.Eq("article_tags.tag_id", filter.TagID))
+	}
+
+	if filter.CategoryID > 0 {
+		associate = append(associate, "JOIN article_categories ON article_categories.article_id = articles.id")
+		where = append(where, paginator.Eq("article_categories.category_id", filter.CategoryID))
+	}
+
+	return associate, paginator.And(where...)
+}
+
+// articleSearch reads the full text query of filter, nil when there is none.
+func articleSearch(filter domain.GetArticlesFilter) *domain.ArticleSearch {
+	if strings.TrimSpace(filter.Search) == "" {
+		return nil
+	}
+
+	mode := filter.SearchMode
+	if mode == "" {
+		mode = domain.ArticleSearchNatural
+	}
+	return &domain.ArticleSearch{Text: filter.Search, Mode: mode}
+}
+
+// articleResponse maps the listed articles, the results of a search also
+// carry their relevance and a snippet of the body around the matches.
+func (auc articleUseCase) articleResponse(query *domain.ArticleSearch) func(domain.Article) domain.GetArticleResponse {
+	if query == nil {
+		return domain.Article.ToArticleResponse
+	}
+
+	match := auc.searchRepo.Matcher(domain.SearchQuery{Text: query.Text, Mode: query.Mode})
+	return func(v domain.Article) domain.GetArticleResponse {
+		res := v.ToArticleResponse()
+		score := v.Score
+		res.Score = &score
+		res.Snippet = search.Snippet(v.Body, match, snippetSize)
+		return res
+	}
+}
+
+// authorizeOwner allows the article owner and admins, anyone else gets domain.ErrForbidden.
+func (auc articleUseCase) authorizeOwner(beegoCtx *beegoContext.Context, article *domain.Article) error {
+	userID, err := helper.GetUserID(auc.jwtAuth, beegoCtx.Request)
+	if err != nil {
+		return err
+	}
+
+	if article.AuthorID == userID || helper.HasRole(auc.jwtAuth, beegoCtx.Request, domain.RoleAdmin) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
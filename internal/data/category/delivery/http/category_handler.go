@@ -0,0 +1,165 @@
+package http
+
+import (
+	"article-app/internal"
+	"article-app/internal/domain"
+	"article-app/internal/middlewares"
+	"article-app/pkg/database/paginator"
+	"article-app/pkg/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type categoryHandler struct {
+	internal.BaseController
+	response.ApiResponse
+	CategoryUseCase domain.CategoryUseCase
+}
+
+func NewCategoryHandler(useCase domain.CategoryUseCase, rbac *middlewares.RbacConfig) {
+	pHandler := &categoryHandler{
+		CategoryUseCase: useCase,
+	}
+	beego.Router("/api/v1/cms/category", pHandler, "post:CreateCategory")
+	beego.Router("/api/v1/cms/category", pHandler, "get:GetCategories")
+	beego.Router("/api/v1/cms/category/:id", pHandler, "get:GetCategoryById")
+	beego.Router("/api/v1/cms/category/:id", pHandler, "patch:UpdateCategory")
+	beego.Router("/api/v1/cms/category/:id", pHandler, "delete:DeleteCategory")
+
+	// required permissions
+	beego.InsertFilterChain("/api/v1/cms/category", rbac.Require(http.MethodPost, domain.PermissionTaxonomyManage))
+	beego.InsertFilterChain("/api/v1/cms/category", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/category/:id", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/category/:id", rbac.Require(http.MethodPatch, domain.PermissionTaxonomyManage))
+	beego.InsertFilterChain("/api/v1/cms/category/:id", rbac.Require(http.MethodDelete, domain.PermissionTaxonomyManage))
+}
+
+func (h *categoryHandler) Prepare() {
+	// check user access when needed
+	h.SetLangVersion()
+}
+
+func (h *categoryHandler) CreateCategory() {
+	var request domain.CategoryRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), nil)
+		return
+	}
+
+	data, err := h.CategoryUseCase.CreateCategory(h.Ctx, request)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+// GetCategories
+// @Title GetCategories
+// @Summary List categories
+// @Produce json
+// @Tags Category
+// @Success 200 {object} swagger.ListResponse{data=[]domain.CategoryResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total records"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Param sort query string false "e.g. name,-created_at"
+// @Security ApiKeyAuth
+// @Router /v1/cms/category [get]
+func (h *categoryHandler) GetCategories() {
+	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	sort, err := domain.SortQueryParamValidation(domain.CategorySort, h.Ctx.Input.Query("sort"), "", "")
+	if err != nil {
+		h.ResponseSortError(err)
+		return
+	}
+
+	limit, page, _ := paginator.Pagination(page, pageSize)
+
+	result, err := h.CategoryUseCase.GetCategories(h.Ctx, page, limit, sort)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
+	return
+}
+
+func (h *categoryHandler) GetCategoryById() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.CategoryUseCase.GetCategoryById(h.Ctx, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+func (h *categoryHandler) UpdateCategory() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.CategoryRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), nil)
+		return
+	}
+
+	data, err := h.CategoryUseCase.UpdateCategory(h.Ctx, request, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+func (h *categoryHandler) DeleteCategory() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err = h.CategoryUseCase.DeleteCategory(h.Ctx, pathParam); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) domain.CategoryRepository {
+	return &CategoryRepository{
+		db: db,
+	}
+}
+
+func (cr CategoryRepository) Store(ctx context.Context, data domain.Category) (int, error) {
+	err := cr.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return 0, err
+	}
+	return data.ID, nil
+}
+
+func (cr CategoryRepository) Fetch(ctx context.Context, page, limit int, sort paginator.Sort) (*paginator.Page[domain.Category], error) {
+	return paginator.NewPaginator[domain.Category](cr.db, page, limit).FindWithFilter(ctx, sort.OrderBy(), nil, nil, nil)
+}
+
+func (cr CategoryRepository) FindByID(ctx context.Context, id int) (*domain.Category, error) {
+	var entity domain.Category
+	err := cr.db.WithContext(ctx).First(&entity, "id =?", id).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+func (cr CategoryRepository) FindByIDs(ctx context.Context, ids []int) ([]domain.Category, error) {
+	var entities []domain.Category
+	if len(ids) == 0 {
+		return entities, nil
+	}
+
+	err := cr.db.WithContext(ctx).Where("id IN ?", ids).Find(&entities).Error
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (cr CategoryRepository) FindByName(ctx context.Context, name string) (*domain.Category, error) {
+	var entity domain.Category
+	err := cr.db.WithContext(ctx).First(&entity, "name =?", name).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+func (cr CategoryRepository) Update(ctx context.Context, data domain.Category, id int) error {
+	err := cr.db.WithContext(ctx).Where("categories.id = ?", id).Updates(&data).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes the category together with its article assignments. The version
+// of the articles is bumped, so the search sync and the ETags see the change.
+func (cr CategoryRepository) Delete(ctx context.Context, id int) error {
+	return cr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("update articles set version = version + 1 where id in (select article_id from article_categories where category_id =?)", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("delete from article_categories where category_id =?", id).Error; err != nil {
+			return err
+		}
+		return tx.Exec("delete from categories where id =?", id).Error
+	})
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"context"
+	"errors"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+type categoryUseCase struct {
+	contextTimeout time.Duration
+	categoryRepo   domain.CategoryRepository
+}
+
+func NewCategoryUseCase(timeout time.Duration, cr domain.CategoryRepository) domain.CategoryUseCase {
+	return &categoryUseCase{
+		contextTimeout: timeout,
+		categoryRepo:   cr,
+	}
+}
+
+func (cuc categoryUseCase) CreateCategory(beegoCtx *beegoContext.Context, body domain.CategoryRequest) (*domain.CategoryResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	if err := cuc.checkNameAvailable(ctx, body.Name, 0); err != nil {
+		return nil, err
+	}
+
+	id, err := cuc.categoryRepo.Store(ctx, body.ToCategory())
+	if err != nil {
+		return nil, err
+	}
+
+	return cuc.findResponse(ctx, id)
+}
+
+func (cuc categoryUseCase) GetCategories(beegoCtx *beegoContext.Context, page, limit int, sort paginator.Sort) (result *paginator.Page[domain.CategoryResponse], err error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	paging, err := cuc.categoryRepo.Fetch(ctx, page, limit, sort)
+	if err != nil {
+		return nil, err
+	}
+	return paginator.MapPage(paging, domain.Category.ToCategoryResponse), nil
+}
+
+func (cuc categoryUseCase) GetCategoryById(beegoCtx *beegoContext.Context, id int) (*domain.CategoryResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	return cuc.findResponse(ctx, id)
+}
+
+func (cuc categoryUseCase) UpdateCategory(beegoCtx *beegoContext.Context, body domain.CategoryRequest, id int) (*domain.CategoryResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	if _, err := cuc.categoryRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := cuc.checkNameAvailable(ctx, body.Name, id); err != nil {
+		return nil, err
+	}
+
+	if err := cuc.categoryRepo.Update(ctx, body.ToCategory(), id); err != nil {
+		return nil, err
+	}
+
+	return cuc.findResponse(ctx, id)
+}
+
+func (cuc categoryUseCase) DeleteCategory(beegoCtx *beegoContext.Context, id int) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	if _, err := cuc.categoryRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	return cuc.categoryRepo.Delete(ctx, id)
+}
+
+// checkNameAvailable returns domain.ErrConflict when another category already uses name.
+func (cuc categoryUseCase) checkNameAvailable(ctx context.Context, name string, id int) error {
+	found, err := cuc.categoryRepo.FindByName(ctx, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if found.ID != id {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+func (cuc categoryUseCase) findResponse(ctx context.Context, id int) (*domain.CategoryResponse, error) {
+	data, err := cuc.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := data.ToCategoryResponse()
+	return &res, nil
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"article-app/internal"
+	"article-app/internal/domain"
+	"article-app/internal/middlewares"
+	"article-app/pkg/database/paginator"
+	"article-app/pkg/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+type commentHandler struct {
+	internal.BaseController
+	response.ApiResponse
+	CommentUseCase domain.CommentUseCase
+}
+
+func NewCommentHandler(useCase domain.CommentUseCase, rbac *middlewares.RbacConfig) {
+	pHandler := &commentHandler{
+		CommentUseCase: useCase,
+	}
+	beego.Router("/api/v1/cms/article/:id/comments", pHandler, "post:CreateComment")
+	beego.Router("/api/v1/cms/article/:id/comments", pHandler, "get:GetComments")
+	beego.Router("/api/v1/cms/comment/pending", pHandler, "get:GetPendingComments")
+	beego.Router("/api/v1/cms/comment/:id/approve", pHandler, "post:ApproveComment")
+	beego.Router("/api/v1/cms/comment/:id/reject", pHandler, "post:RejectComment")
+	beego.Router("/api/v1/cms/comment/:id", pHandler, "delete:DeleteComment")
+
+	// required permissions
+	beego.InsertFilterChain("/api/v1/cms/article/:id/comments", rbac.Require(http.MethodPost, domain.PermissionCommentCreate))
+	beego.InsertFilterChain("/api/v1/cms/article/:id/comments", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/comment/pending", rbac.Require(http.MethodGet, domain.PermissionCommentModerate))
+	beego.InsertFilterChain("/api/v1/cms/comment/:id/approve", rbac.Require(http.MethodPost, domain.PermissionCommentModerate))
+	beego.InsertFilterChain("/api/v1/cms/comment/:id/reject", rbac.Require(http.MethodPost, domain.PermissionCommentModerate))
+	beego.InsertFilterChain("/api/v1/cms/comment/:id", rbac.Require(http.MethodDelete, domain.PermissionCommentModerate))
+}
+
+func (h *commentHandler) Prepare() {
+	// check user access when needed
+	h.SetLangVersion()
+}
+
+func (h *commentHandler) CreateComment() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.CreateCommentRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	request.Body = strings.TrimSpace(request.Body)
+	if request.Body == "" {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), nil)
+		return
+	}
+
+	data, err := h.CommentUseCase.CreateComment(h.Ctx, request, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+// GetComments
+// @Title GetComments
+// @Summary List the approved comment threads of an article
+// @Produce json
+// @Tags Comment
+// @Success 200 {object} swagger.ListResponse{data=[]domain.CommentResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total records"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param id path int true "article id"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Security ApiKeyAuth
+// @Router /v1/cms/article/{id}/comments [get]
+func (h *commentHandler) GetComments() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	limit, page, _ := paginator.Pagination(page, pageSize)
+
+	result, err := h.CommentUseCase.GetComments(h.Ctx, pathParam, page, limit)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
+	return
+}
+
+// GetPendingComments
+// @Title GetPendingComments
+// @Summary List the comments waiting for moderation
+// @Produce json
+// @Tags Comment
+// @Success 200 {object} swagger.ListResponse{data=[]domain.CommentResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total records"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Security ApiKeyAuth
+// @Router /v1/cms/comment/pending [get]
+func (h *commentHandler) GetPendingComments() {
+	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	limit, page, _ := paginator.Pagination(page, pageSize)
+
+	result, err := h.CommentUseCase.GetPendingComments(h.Ctx, page, limit)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
+	return
+}
+
+func (h *commentHandler) ApproveComment() {
+	h.moderate(h.CommentUseCase.ApproveComment)
+}
+
+func (h *commentHandler) RejectComment() {
+	h.moderate(h.CommentUseCase.RejectComment)
+}
+
+func (h *commentHandler) DeleteComment() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err = h.CommentUseCase.DeleteComment(h.Ctx, pathParam); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+func (h *commentHandler) moderate(useCase func(ctx *beegoContext.Context, id int) (*domain.CommentResponse, error)) {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	data, err := useCase(h.Ctx, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
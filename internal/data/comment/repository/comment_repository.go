@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) domain.CommentRepository {
+	return &CommentRepository{
+		db: db,
+	}
+}
+
+func (cr CommentRepository) Store(ctx context.Context, data domain.Comment) (int, error) {
+	err := cr.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return 0, err
+	}
+	return data.ID, nil
+}
+
+func (cr CommentRepository) FindByID(ctx context.Context, id int) (*domain.Comment, error) {
+	var entity domain.Comment
+	err := cr.db.WithContext(ctx).Preload("Author").First(&entity, "id =?", id).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+// FetchByArticle pages through the approved top level comments of the article, oldest first.
+func (cr CommentRepository) FetchByArticle(ctx context.Context, articleID, page, limit int) (*paginator.Page[domain.Comment], error) {
+	p := paginator.NewPaginator[domain.Comment](cr.db, page, limit)
+	criteria := paginator.And(
+		paginator.Eq("comments.article_id", articleID),
+		paginator.IsNull("comments.parent_id"),
+		paginator.Eq("comments.status", domain.CommentStatusApproved),
+	)
+	return p.FindWithFilter(ctx, "comments.created_at asc, comments.id asc", nil, []string{"Author"}, criteria)
+}
+
+// FindRepliesByArticle returns every approved reply posted under the article.
+func (cr CommentRepository) FindRepliesByArticle(ctx context.Context, articleID int) ([]domain.Comment, error) {
+	var entities []domain.Comment
+	err := cr.db.WithContext(ctx).Joins("Author").
+		Where("comments.article_id = ? AND comments.parent_id IS NOT NULL AND comments.status = ?", articleID, domain.CommentStatusApproved).
+		Order("comments.created_at asc, comments.id asc").
+		Find(&entities).Error
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// FetchPending is the moderation queue, oldest first.
+func (cr CommentRepository) FetchPending(ctx context.Context, page, limit int) (*paginator.Page[domain.Comment], error) {
+	p := paginator.NewPaginator[domain.Comment](cr.db, page, limit)
+	criteria := paginator.Eq("comments.status", domain.CommentStatusPending)
+	return p.FindWithFilter(ctx, "comments.created_at asc, comments.id asc", nil, []string{"Author"}, criteria)
+}
+
+func (cr CommentRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	err := cr.db.WithContext(ctx).Model(&domain.Comment{}).Where("comments.id = ?", id).Update("status", status).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes the comment together with the whole thread of replies below it.
+func (cr CommentRepository) Delete(ctx context.Context, id int) error {
+	return cr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []int{id}
+		for parents := ids; len(parents) > 0; {
+			var children []int
+			if err := tx.Model(&domain.Comment{}).Where("parent_id IN ?", parents).Pluck("id", &children).Error; err != nil {
+				return err
+			}
+			ids = append(ids, children...)
+			parents = children
+		}
+
+		// replies go first so the parent foreign key is never violated
+		for i := len(ids) - 1; i >= 0; i-- {
+			if err := tx.Exec("delete from comments where id =?", ids[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"article-app/pkg/helper"
+	"article-app/pkg/jwt"
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+type commentUseCase struct {
+	contextTimeout time.Duration
+	commentRepo    domain.CommentRepository
+	articleRepo    domain.ArticleRepository
+	jwtAuth        jwt.JWT
+}
+
+func NewCommentUseCase(timeout time.Duration, cr domain.CommentRepository, ar domain.ArticleRepository, jwtAuth jwt.JWT) domain.CommentUseCase {
+	return &commentUseCase{
+		contextTimeout: timeout,
+		commentRepo:    cr,
+		articleRepo:    ar,
+		jwtAuth:        jwtAuth,
+	}
+}
+
+// CreateComment queues the comment for moderation, it is not shown until approved.
+// Only published articles take comments, the others are reported as
+// domain.ErrNotFound like the public lookup does.
+func (cuc commentUseCase) CreateComment(beegoCtx *beegoContext.Context, body domain.CreateCommentRequest, articleID int) (*domain.CommentResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	authorID, err := helper.GetUserID(cuc.jwtAuth, beegoCtx.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := cuc.articleRepo.FindByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != domain.ArticleStatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	if body.ParentID != nil {
+		parent, err := cuc.commentRepo.FindByID(ctx, *body.ParentID)
+		if err != nil || parent.ArticleID != articleID {
+			return nil, domain.ErrInvalidParentComment
+		}
+	}
+
+	id, err := cuc.commentRepo.Store(ctx, body.ToComment(articleID, authorID))
+	if err != nil {
+		return nil, err
+	}
+
+	return cuc.findResponse(ctx, id)
+}
+
+// GetComments pages through the approved threads of the article, each top level
+// comment carries its approved replies nested below it.
+func (cuc commentUseCase) GetComments(beegoCtx *beegoContext.Context, articleID, page, limit int) (result *paginator.Page[domain.CommentResponse], err error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	if _, err = cuc.articleRepo.FindByID(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	paging, err := cuc.commentRepo.FetchByArticle(ctx, articleID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := cuc.commentRepo.FindRepliesByArticle(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[int][]domain.Comment)
+	for _, v := range replies {
+		children[*v.ParentID] = append(children[*v.ParentID], v)
+	}
+
+	return paginator.MapPage(paging, func(v domain.Comment) domain.CommentResponse {
+		return buildThread(v, children)
+	}), nil
+}
+
+func (cuc commentUseCase) GetPendingComments(beegoCtx *beegoContext.Context, page, limit int) (result *paginator.Page[domain.CommentResponse], err error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	paging, err := cuc.commentRepo.FetchPending(ctx, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return paginator.MapPage(paging, domain.Comment.ToCommentResponse), nil
+}
+
+func (cuc commentUseCase) ApproveComment(beegoCtx *beegoContext.Context, id int) (*domain.CommentResponse, error) {
+	return cuc.moderate(beegoCtx, id, domain.CommentStatusApproved)
+}
+
+func (cuc commentUseCase) RejectComment(beegoCtx *beegoContext.Context, id int) (*domain.CommentResponse, error) {
+	return cuc.moderate(beegoCtx, id, domain.CommentStatusRejected)
+}
+
+func (cuc commentUseCase) DeleteComment(beegoCtx *beegoContext.Context, id int) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	if _, err := cuc.commentRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	return cuc.commentRepo.Delete(ctx, id)
+}
+
+// moderate settles a pending comment, a comment is moderated only once.
+func (cuc commentUseCase) moderate(beegoCtx *beegoContext.Context, id int, status string) (*domain.CommentResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), cuc.contextTimeout)
+	defer cancel()
+
+	found, err := cuc.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if found.Status != domain.CommentStatusPending {
+		return nil, domain.ErrCommentNotPending
+	}
+
+	if err = cuc.commentRepo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+
+	return cuc.findResponse(ctx, id)
+}
+
+func (cuc commentUseCase) findResponse(ctx context.Context, id int) (*domain.CommentResponse, error) {
+	data, err := cuc.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := data.ToCommentResponse()
+	return &res, nil
+}
+
+// buildThread nests the replies found in children below comment.
+func buildThread(comment domain.Comment, children map[int][]domain.Comment) domain.CommentResponse {
+	res := comment.ToCommentResponse()
+	for _, v := range children[comment.ID] {
+		res.Replies = append(res.Replies, buildThread(v, children))
+	}
+	return res
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"article-app/internal"
+	"article-app/internal/domain"
+	"article-app/pkg/response"
+
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type errorCodeHandler struct {
+	internal.BaseController
+	response.ApiResponse
+}
+
+func NewErrorCodeHandler() {
+	pHandler := &errorCodeHandler{}
+	beego.Router("/api/v1/errors", pHandler, "get:GetErrorCodes")
+}
+
+func (h *errorCodeHandler) Prepare() {
+	// check user access when needed
+	h.SetLangVersion()
+}
+
+// GetErrorCodes
+// @Title GetErrorCodes
+// @Summary List the error codes of the api with their HTTP status and message
+// @Produce json
+// @Tags Error
+// @Success 200 {object} swagger.BaseResponse{data=[]domain.ErrorCodeResponse}
+// @Param Accept-Language header string false "lang"
+// @Param lang query string false "en or id, the messages are in this locale"
+// @Router /v1/errors [get]
+func (h *errorCodeHandler) GetErrorCodes() {
+	h.Ok(h.Ctx, h.Tr("message.success"), domain.ErrorCatalog(h.Lang))
+	return
+}
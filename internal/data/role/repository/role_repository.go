@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type roleRepository struct {
+	DB *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) domain.RoleRepository {
+	return &roleRepository{
+		DB: db,
+	}
+}
+
+func (rr roleRepository) FindByNames(ctx context.Context, names []string) ([]domain.Role, error) {
+	var entities []domain.Role
+	err := rr.DB.WithContext(ctx).Where("name IN ?", names).Find(&entities).Error
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (rr roleRepository) FindPermissionsByRoles(ctx context.Context, roles []string) ([]string, error) {
+	var permissions []string
+	if len(roles) == 0 {
+		return permissions, nil
+	}
+
+	err := rr.DB.WithContext(ctx).
+		Table("permissions").
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name IN ?", roles).
+		Pluck("permissions.name", &permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"article-app/internal"
+	"article-app/internal/domain"
+	"article-app/internal/middlewares"
+	"article-app/pkg/database/paginator"
+	"article-app/pkg/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type searchHandler struct {
+	internal.BaseController
+	response.ApiResponse
+	SearchUseCase domain.SearchUseCase
+}
+
+func NewSearchHandler(useCase domain.SearchUseCase, rbac *middlewares.RbacConfig) {
+	pHandler := &searchHandler{
+		SearchUseCase: useCase,
+	}
+	beego.Router("/api/v1/cms/search", pHandler, "get:Search")
+
+	// required permissions
+	beego.InsertFilterChain("/api/v1/cms/search", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+}
+
+func (h *searchHandler) Prepare() {
+	// check user access when needed
+	h.SetLangVersion()
+}
+
+// Search
+// @Title Search
+// @Summary Search the articles in the embedded index, with the hit counts by author and tag
+// @Produce json
+// @Tags Search
+// @Success 200 {object} swagger.ListResponse{data=domain.SearchResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total hits"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param q query string false "full text query in English or Indonesian, every article without it"
+// @Param search_mode query string false "natural (default) or boolean, boolean supports + - * and quoted phrases"
+// @Param author query int false "author id"
+// @Param tag query int false "tag id"
+// @Param status query string false "draft, in_review, published or archived"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Security ApiKeyAuth
+// @Router /v1/cms/search [get]
+func (h *searchHandler) Search() {
+	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	query := domain.SearchQuery{
+		Text:   strings.TrimSpace(h.Ctx.Input.Query("q")),
+		Mode:   h.Ctx.Input.Query("search_mode"),
+		Status: h.Ctx.Input.Query("status"),
+	}
+
+	if query.Mode != "" && !domain.IsValidArticleSearchMode(query.Mode) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+		return
+	}
+
+	if query.Status != "" && !domain.IsValidArticleStatus(query.Status) {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+		return
+	}
+
+	if author := h.Ctx.Input.Query("author"); author != "" {
+		authorID, err := strconv.Atoi(author)
+		if err != nil || authorID < 1 {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+			return
+		}
+		query.AuthorID = authorID
+	}
+
+	if tag := h.Ctx.Input.Query("tag"); tag != "" {
+		tagID, err := strconv.Atoi(tag)
+		if err != nil || tagID < 1 {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), domain.ErrQueryParamInvalid)
+			return
+		}
+		query.TagID = tagID
+	}
+
+	limit, page, _ := paginator.Pagination(page, pageSize)
+
+	result, facets, err := h.SearchUseCase.Search(h.Ctx, query, page, limit)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	data := domain.SearchResponse{Hits: result.Records, Facets: *facets}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), data, response.PagePagination(result))
+	return
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/search"
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// rebuildBatchSize is how many articles a rebuild reads at once.
+const rebuildBatchSize = 500
+
+// SearchRepository keeps the articles in an index embedded in the process
+// and saves it to path, so a restart does not have to read every article.
+type SearchRepository struct {
+	db    *gorm.DB
+	index *search.Index
+	path  string
+}
+
+func NewSearchRepository(db *gorm.DB, index *search.Index, path string) domain.SearchRepository {
+	return &SearchRepository{
+		db:    db,
+		index: index,
+		path:  path,
+	}
+}
+
+// Index puts the article with its author and tags, as loaded by FindByID.
+func (sr SearchRepository) Index(ctx context.Context, article domain.Article) error {
+	doc, err := searchDocument(article)
+	if err != nil {
+		return err
+	}
+	sr.index.Put(doc)
+	return nil
+}
+
+func (sr SearchRepository) Remove(ctx context.Context, id int) error {
+	sr.index.Delete(id)
+	return nil
+}
+
+func (sr SearchRepository) Search(ctx context.Context, query domain.SearchQuery, offset, limit int) (*domain.SearchResult, error) {
+	opts := search.Options{
+		Filters: make(map[string]string),
+		Facets:  []string{domain.SearchFacetAuthor, domain.SearchFacetTag},
+		Offset:  offset,
+		Limit:   limit,
+	}
+	if query.AuthorID > 0 {
+		opts.Filters[domain.SearchFacetAuthor] = strconv.Itoa(query.AuthorID)
+	}
+	if query.TagID > 0 {
+		opts.Filters[domain.SearchFacetTag] = strconv.Itoa(query.TagID)
+	}
+	if query.Status != "" {
+		opts.Filters[domain.SearchFacetStatus] = query.Status
+	}
+
+	found := sr.index.Search(parseQuery(query), opts)
+
+	res := &domain.SearchResult{
+		Total: int64(found.Total),
+		Hits:  make([]domain.SearchHit, len(found.Hits)),
+	}
+	for k, v := range found.Hits {
+		res.Hits[k].Score = v.Score
+		if err := json.Unmarshal(v.Stored, &res.Hits[k].SearchDocument); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if res.Facets.Author, err = sr.facets(ctx, &domain.User{}, "email", found.Facets[domain.SearchFacetAuthor]); err != nil {
+		return nil, err
+	}
+	if res.Facets.Tag, err = sr.facets(ctx, &domain.Tag{}, "name", found.Facets[domain.SearchFacetTag]); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// facets names the counted ids with the column label of model, an id that
+// is gone from the database keeps an empty name until the next rebuild.
+func (sr SearchRepository) facets(ctx context.Context, model interface{}, label string, counts []search.FacetCount) ([]domain.SearchFacet, error) {
+	res := make([]domain.SearchFacet, len(counts))
+	ids := make([]int, 0, len(counts))
+	for k, v := range counts {
+		res[k].ID, _ = strconv.Atoi(v.Value)
+		res[k].Count = v.Count
+		ids = append(ids, res[k].ID)
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	var rows []struct {
+		ID    int
+		Label string
+	}
+	err := sr.db.WithContext(ctx).Model(model).Select("id", label+" AS label").Where("id IN ?", ids).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string, len(rows))
+	for _, v := range rows {
+		names[v.ID] = v.Label
+	}
+	for k := range res {
+		res[k].Name = names[res[k].ID]
+	}
+	return res, nil
+}
+
+func (sr SearchRepository) Matcher(query domain.SearchQuery) func(word string) bool {
+	return sr.index.Analyzer().Matcher(parseQuery(query))
+}
+
+// Rebuild indexes the articles on the side and swaps the index at once, the
+// searches keep using the previous content meanwhile. The writes made while
+// the articles are read are applied again after the swap.
+func (sr SearchRepository) Rebuild(ctx context.Context) error {
+	fresh := search.NewIndex(sr.index.Analyzer())
+	sr.index.BeginRebuild()
+
+	var batch []domain.Article
+	err := sr.db.WithContext(ctx).Preload("Author").Preload("Tags").FindInBatches(&batch, rebuildBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, v := range batch {
+			doc, err := searchDocument(v)
+			if err != nil {
+				return err
+			}
+			fresh.Put(doc)
+		}
+		return nil
+	}).Error
+	if err != nil {
+		sr.index.CancelRebuild()
+		return err
+	}
+
+	sr.index.Replace(fresh)
+	return nil
+}
+
+// Sync compares the version of each indexed article with the database and
+// indexes again the ones that differ, which catches the writes lost by a
+// crash before a flush or made by another instance. The versions are taken
+// before the database is read, a write racing with Sync is not undone since
+// an older version never replaces a newer one.
+func (sr SearchRepository) Sync(ctx context.Context) error {
+	indexed := sr.index.Versions()
+
+	var rows []struct {
+		ID      int
+		Version int
+	}
+	if err := sr.db.WithContext(ctx).Model(&domain.Article{}).Select("id", "version").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	var stale []int
+	for _, v := range rows {
+		if version, ok := indexed[v.ID]; !ok || version != v.Version {
+			stale = append(stale, v.ID)
+		}
+		delete(indexed, v.ID)
+	}
+	for id := range indexed {
+		sr.index.Delete(id)
+	}
+
+	for len(stale) > 0 {
+		ids := stale
+		if len(ids) > rebuildBatchSize {
+			ids = ids[:rebuildBatchSize]
+		}
+		stale = stale[len(ids):]
+
+		var batch []domain.Article
+		if err := sr.db.WithContext(ctx).Preload("Author").Preload("Tags").Where("id IN ?", ids).Find(&batch).Error; err != nil {
+			return err
+		}
+		for _, v := range batch {
+			if err := sr.Index(ctx, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (sr SearchRepository) Flush() error {
+	if !sr.index.Dirty() {
+		return nil
+	}
+	return sr.index.Save(sr.path)
+}
+
+func parseQuery(query domain.SearchQuery) search.Query {
+	return search.ParseQuery(query.Text, query.Mode == domain.ArticleSearchBoolean)
+}
+
+// searchDocument weighs a word of the title like three words of the body and
+// stores what a hit lists.
+func searchDocument(article domain.Article) (search.Document, error) {
+	stored, err := json.Marshal(article.ToSearchDocument())
+	if err != nil {
+		return search.Document{}, err
+	}
+
+	tags := make([]string, len(article.Tags))
+	for k, v := range article.Tags {
+		tags[k] = strconv.Itoa(v.ID)
+	}
+
+	return search.Document{
+		ID:      article.ID,
+		Version: article.Version,
+		Fields: []search.Field{
+			{Text: article.Title, Boost: 3},
+			{Text: article.Body, Boost: 1},
+		},
+		Facets: map[string][]string{
+			domain.SearchFacetAuthor: {strconv.Itoa(article.AuthorID)},
+			domain.SearchFacetTag:    tags,
+			domain.SearchFacetStatus: {article.Status},
+		},
+		Stored: stored,
+	}, nil
+}
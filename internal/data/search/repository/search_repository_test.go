@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/search"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&domain.User{}, &domain.Tag{}, &domain.Category{}, &domain.Article{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createArticle(t *testing.T, db *gorm.DB, authorID int, title string, tags ...domain.Tag) domain.Article {
+	t.Helper()
+	article := domain.Article{
+		AuthorID: authorID,
+		Title:    title,
+		Slug:     title,
+		Body:     "body",
+		Status:   domain.ArticleStatusDraft,
+		Version:  1,
+		Tags:     tags,
+	}
+	if err := db.Create(&article).Error; err != nil {
+		t.Fatal(err)
+	}
+	return article
+}
+
+func TestSearchRepositorySync(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	author := domain.User{Email: "author@mail.com", Password: "Password123"}
+	if err := db.Create(&author).Error; err != nil {
+		t.Fatal(err)
+	}
+	generics := createArticle(t, db, author.Id, "go generics", domain.Tag{Name: "go"})
+	pasta := createArticle(t, db, author.Id, "cooking pasta")
+	java := createArticle(t, db, author.Id, "java streams")
+
+	index := search.NewIndex(search.Analyzer{Languages: search.LookupLanguages("en")})
+	repo := NewSearchRepository(db, index, filepath.Join(t.TempDir(), "search.idx"))
+	if err := repo.Rebuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// changes the index missed, like the ones of another instance
+	db.Model(&domain.Article{}).Where("id = ?", generics.ID).Updates(map[string]interface{}{"title": "rust ownership", "version": 2})
+	db.Delete(&domain.Article{}, pasta.ID)
+	kotlin := createArticle(t, db, author.Id, "kotlin coroutines")
+
+	if err := repo.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]int{generics.ID: 2, java.ID: 1, kotlin.ID: 1}
+	if got := index.Versions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Versions() = %v, want %v", got, want)
+	}
+	for query, ids := range map[string][]int{
+		"rust":     {generics.ID},
+		"generics": {},
+		"pasta":    {},
+		"kotlin":   {kotlin.ID},
+	} {
+		res, err := repo.Search(ctx, domain.SearchQuery{Text: query}, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []int{}
+		for _, v := range res.Hits {
+			got = append(got, v.ID)
+		}
+		if !reflect.DeepEqual(got, ids) {
+			t.Errorf("Search(%q) = %v, want %v", query, got, ids)
+		}
+	}
+
+	// the reindexed article keeps its tag facet
+	res, err := repo.Search(ctx, domain.SearchQuery{Text: "rust"}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Facets.Tag) != 1 || res.Facets.Tag[0].Name != "go" {
+		t.Errorf("tag facets = %+v", res.Facets.Tag)
+	}
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"article-app/pkg/search"
+	"context"
+	"math"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+// snippetSize is the length in characters of the body snippet of a hit.
+const snippetSize = 200
+
+type searchUseCase struct {
+	contextTimeout time.Duration
+	searchRepo     domain.SearchRepository
+}
+
+func NewSearchUseCase(timeout time.Duration, sr domain.SearchRepository) domain.SearchUseCase {
+	return &searchUseCase{
+		contextTimeout: timeout,
+		searchRepo:     sr,
+	}
+}
+
+func (suc searchUseCase) Search(beegoCtx *beegoContext.Context, query domain.SearchQuery, page, limit int) (*paginator.Page[domain.SearchHitResponse], *domain.SearchFacets, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), suc.contextTimeout)
+	defer cancel()
+
+	if query.Mode == "" {
+		query.Mode = domain.ArticleSearchNatural
+	}
+
+	found, err := suc.searchRepo.Search(ctx, query, (page-1)*limit, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &paginator.Page[domain.SearchHitResponse]{
+		MaxPage:     int64(math.Ceil(float64(found.Total) / float64(limit))),
+		Total:       found.Total,
+		PageSize:    limit,
+		CurrentPage: page,
+		Records:     make([]domain.SearchHitResponse, len(found.Hits)),
+	}
+	if res.MaxPage == 0 {
+		res.MaxPage = 1
+	}
+
+	match := suc.searchRepo.Matcher(query)
+	for k, v := range found.Hits {
+		res.Records[k] = domain.SearchHitResponse{
+			ID:        v.ID,
+			Title:     v.Title,
+			Slug:      v.Slug,
+			Status:    v.Status,
+			Author:    v.Author,
+			Tags:      v.Tags,
+			UpdatedAt: v.UpdatedAt,
+			Score:     v.Score,
+			Snippet:   search.Snippet(v.Body, match, snippetSize),
+		}
+	}
+	return res, &found.Facets, nil
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"article-app/internal"
+	"article-app/internal/domain"
+	"article-app/internal/middlewares"
+	"article-app/pkg/database/paginator"
+	"article-app/pkg/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type tagHandler struct {
+	internal.BaseController
+	response.ApiResponse
+	TagUseCase domain.TagUseCase
+}
+
+func NewTagHandler(useCase domain.TagUseCase, rbac *middlewares.RbacConfig) {
+	pHandler := &tagHandler{
+		TagUseCase: useCase,
+	}
+	beego.Router("/api/v1/cms/tag", pHandler, "post:CreateTag")
+	beego.Router("/api/v1/cms/tag", pHandler, "get:GetTags")
+	beego.Router("/api/v1/cms/tag/:id", pHandler, "get:GetTagById")
+	beego.Router("/api/v1/cms/tag/:id", pHandler, "patch:UpdateTag")
+	beego.Router("/api/v1/cms/tag/:id", pHandler, "delete:DeleteTag")
+
+	// required permissions
+	beego.InsertFilterChain("/api/v1/cms/tag", rbac.Require(http.MethodPost, domain.PermissionTaxonomyManage))
+	beego.InsertFilterChain("/api/v1/cms/tag", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/tag/:id", rbac.Require(http.MethodGet, domain.PermissionArticleRead))
+	beego.InsertFilterChain("/api/v1/cms/tag/:id", rbac.Require(http.MethodPatch, domain.PermissionTaxonomyManage))
+	beego.InsertFilterChain("/api/v1/cms/tag/:id", rbac.Require(http.MethodDelete, domain.PermissionTaxonomyManage))
+}
+
+func (h *tagHandler) Prepare() {
+	// check user access when needed
+	h.SetLangVersion()
+}
+
+func (h *tagHandler) CreateTag() {
+	var request domain.TagRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), nil)
+		return
+	}
+
+	data, err := h.TagUseCase.CreateTag(h.Ctx, request)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+// GetTags
+// @Title GetTags
+// @Summary List tags
+// @Produce json
+// @Tags Tag
+// @Success 200 {object} swagger.ListResponse{data=[]domain.TagResponse}
+// @Header 200 {string} Link "first, prev, next and last page"
+// @Header 200 {integer} X-Total-Count "total records"
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param page query int false "page"
+// @Param pageSize query int false "page size"
+// @Param sort query string false "e.g. name,-created_at"
+// @Security ApiKeyAuth
+// @Router /v1/cms/tag [get]
+func (h *tagHandler) GetTags() {
+	pageSize, page, err := domain.PaginationQueryParamValidation(h.Ctx.Input.Query("pageSize"), h.Ctx.Input.Query("page"))
+	if err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.QueryParamInvalidCode, domain.ErrorCodeText(domain.QueryParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	sort, err := domain.SortQueryParamValidation(domain.TagSort, h.Ctx.Input.Query("sort"), "", "")
+	if err != nil {
+		h.ResponseSortError(err)
+		return
+	}
+
+	limit, page, _ := paginator.Pagination(page, pageSize)
+
+	result, err := h.TagUseCase.GetTags(h.Ctx, page, limit, sort)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
+	return
+}
+
+func (h *tagHandler) GetTagById() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.TagUseCase.GetTagById(h.Ctx, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+func (h *tagHandler) UpdateTag() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.TagRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), nil)
+		return
+	}
+
+	data, err := h.TagUseCase.UpdateTag(h.Ctx, request, pathParam)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), data)
+	return
+}
+
+func (h *tagHandler) DeleteTag() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	if err = h.TagUseCase.DeleteTag(h.Ctx, pathParam); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) domain.TagRepository {
+	return &TagRepository{
+		db: db,
+	}
+}
+
+func (tr TagRepository) Store(ctx context.Context, data domain.Tag) (int, error) {
+	err := tr.db.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return 0, err
+	}
+	return data.ID, nil
+}
+
+func (tr TagRepository) Fetch(ctx context.Context, page, limit int, sort paginator.Sort) (*paginator.Page[domain.Tag], error) {
+	return paginator.NewPaginator[domain.Tag](tr.db, page, limit).FindWithFilter(ctx, sort.OrderBy(), nil, nil, nil)
+}
+
+func (tr TagRepository) FindByID(ctx context.Context, id int) (*domain.Tag, error) {
+	var entity domain.Tag
+	err := tr.db.WithContext(ctx).First(&entity, "id =?", id).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+func (tr TagRepository) FindByIDs(ctx context.Context, ids []int) ([]domain.Tag, error) {
+	var entities []domain.Tag
+	if len(ids) == 0 {
+		return entities, nil
+	}
+
+	err := tr.db.WithContext(ctx).Where("id IN ?", ids).Find(&entities).Error
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (tr TagRepository) FindByName(ctx context.Context, name string) (*domain.Tag, error) {
+	var entity domain.Tag
+	err := tr.db.WithContext(ctx).First(&entity, "name =?", name).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+// Update bumps the version of the articles of the tag when it is renamed, the
+// search index keeps the tag names.
+func (tr TagRepository) Update(ctx context.Context, data domain.Tag, id int) error {
+	return tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tags.id = ?", id).Updates(&data).Error; err != nil {
+			return err
+		}
+		if data.Name == "" {
+			return nil
+		}
+		return tx.Exec("update articles set version = version + 1 where id in (select article_id from article_tags where tag_id =?)", id).Error
+	})
+}
+
+// Delete removes the tag together with its article assignments. The version
+// of the articles is bumped, so the search sync and the ETags see the change.
+func (tr TagRepository) Delete(ctx context.Context, id int) error {
+	return tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("update articles set version = version + 1 where id in (select article_id from article_tags where tag_id =?)", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("delete from article_tags where tag_id =?", id).Error; err != nil {
+			return err
+		}
+		return tx.Exec("delete from tags where id =?", id).Error
+	})
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&domain.User{}, &domain.Tag{}, &domain.Category{}, &domain.Article{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestTagRepositoryBumpsArticleVersions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	author := domain.User{Email: "author@mail.com", Password: "Password123"}
+	if err := db.Create(&author).Error; err != nil {
+		t.Fatal(err)
+	}
+	golang, rust := domain.Tag{Name: "go"}, domain.Tag{Name: "rust"}
+	tagged := domain.Article{AuthorID: author.Id, Title: "tagged", Slug: "tagged", Body: "body", Version: 1, Tags: []domain.Tag{golang}}
+	if err := db.Create(&tagged).Error; err != nil {
+		t.Fatal(err)
+	}
+	golang = tagged.Tags[0]
+	other := domain.Article{AuthorID: author.Id, Title: "other", Slug: "other", Body: "body", Version: 1, Tags: []domain.Tag{rust}}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	versions := func() (int, int) {
+		var a, b domain.Article
+		db.First(&a, tagged.ID)
+		db.First(&b, other.ID)
+		return a.Version, b.Version
+	}
+
+	repo := NewTagRepository(db)
+	if err := repo.Update(ctx, domain.Tag{Name: "golang"}, golang.ID); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := versions(); a != 2 || b != 1 {
+		t.Errorf("versions after rename = %d, %d, want 2, 1", a, b)
+	}
+
+	if err := repo.Delete(ctx, golang.ID); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := versions(); a != 3 || b != 1 {
+		t.Errorf("versions after delete = %d, %d, want 3, 1", a, b)
+	}
+	var count int64
+	db.Table("article_tags").Where("tag_id = ?", golang.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("article_tags of the deleted tag = %d", count)
+	}
+}
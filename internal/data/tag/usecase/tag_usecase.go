@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/database/paginator"
+	"context"
+	"errors"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+type tagUseCase struct {
+	contextTimeout time.Duration
+	tagRepo        domain.TagRepository
+}
+
+func NewTagUseCase(timeout time.Duration, tr domain.TagRepository) domain.TagUseCase {
+	return &tagUseCase{
+		contextTimeout: timeout,
+		tagRepo:        tr,
+	}
+}
+
+func (tuc tagUseCase) CreateTag(beegoCtx *beegoContext.Context, body domain.TagRequest) (*domain.TagResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), tuc.contextTimeout)
+	defer cancel()
+
+	if err := tuc.checkNameAvailable(ctx, body.Name, 0); err != nil {
+		return nil, err
+	}
+
+	id, err := tuc.tagRepo.Store(ctx, body.ToTag())
+	if err != nil {
+		return nil, err
+	}
+
+	return tuc.findResponse(ctx, id)
+}
+
+func (tuc tagUseCase) GetTags(beegoCtx *beegoContext.Context, page, limit int, sort paginator.Sort) (result *paginator.Page[domain.TagResponse], err error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), tuc.contextTimeout)
+	defer cancel()
+
+	paging, err := tuc.tagRepo.Fetch(ctx, page, limit, sort)
+	if err != nil {
+		return nil, err
+	}
+	return paginator.MapPage(paging, domain.Tag.ToTagResponse), nil
+}
+
+func (tuc tagUseCase) GetTagById(beegoCtx *beegoContext.Context, id int) (*domain.TagResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), tuc.contextTimeout)
+	defer cancel()
+
+	return tuc.findResponse(ctx, id)
+}
+
+func (tuc tagUseCase) UpdateTag(beegoCtx *beegoContext.Context, body domain.TagRequest, id int) (*domain.TagResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), tuc.contextTimeout)
+	defer cancel()
+
+	if _, err := tuc.tagRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := tuc.checkNameAvailable(ctx, body.Name, id); err != nil {
+		return nil, err
+	}
+
+	if err := tuc.tagRepo.Update(ctx, body.ToTag(), id); err != nil {
+		return nil, err
+	}
+
+	return tuc.findResponse(ctx, id)
+}
+
+func (tuc tagUseCase) DeleteTag(beegoCtx *beegoContext.Context, id int) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), tuc.contextTimeout)
+	defer cancel()
+
+	if _, err := tuc.tagRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	return tuc.tagRepo.Delete(ctx, id)
+}
+
+// checkNameAvailable returns domain.ErrConflict when another tag already uses name.
+func (tuc tagUseCase) checkNameAvailable(ctx context.Context, name string, id int) error {
+	found, err := tuc.tagRepo.FindByName(ctx, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if found.ID != id {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+func (tuc tagUseCase) findResponse(ctx context.Context, id int) (*domain.TagResponse, error) {
+	data, err := tuc.tagRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := data.ToTagResponse()
+	return &res, nil
+}
@@ -3,11 +3,13 @@ package http
 import (
 	"article-app/internal"
 	"article-app/internal/domain"
+	"article-app/internal/middlewares"
 	"article-app/pkg/jwt"
 	"article-app/pkg/response"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	beego "github.com/beego/beego/v2/server/web"
 )
@@ -19,12 +21,25 @@ type UserHandler struct {
 	JwtAuth     jwt.JWT
 }
 
-func NewUserHandler(useCase domain.UserUseCase, jwt jwt.JWT) {
+func NewUserHandler(useCase domain.UserUseCase, jwt jwt.JWT, rbac *middlewares.RbacConfig) {
 	pHandler := &UserHandler{
 		UserUseCase: useCase,
 		JwtAuth:     jwt,
 	}
+	beego.Router("/api/v1/cms/auth/register", pHandler, "post:Register")
+	beego.Router("/api/v1/cms/auth/verify", pHandler, "post:VerifyEmail")
+	beego.Router("/api/v1/cms/auth/verify/resend", pHandler, "post:ResendVerification")
+	beego.Router("/api/v1/cms/auth/password/forgot", pHandler, "post:ForgotPassword")
+	beego.Router("/api/v1/cms/auth/password/reset", pHandler, "post:ResetPassword")
 	beego.Router("/api/v1/cms/user/login", pHandler, "post:RequestToken")
+	beego.Router("/api/v1/cms/user/refresh", pHandler, "post:RefreshToken")
+	beego.Router("/api/v1/cms/user/logout", pHandler, "post:Logout")
+	beego.Router("/api/v1/cms/user/:id/roles", pHandler, "put:AssignRoles")
+	beego.Router("/api/v1/cms/user/:id/sessions", pHandler, "delete:RevokeSessions")
+
+	// required permissions
+	beego.InsertFilterChain("/api/v1/cms/user/:id/roles", rbac.Require(http.MethodPut, domain.PermissionUserManage))
+	beego.InsertFilterChain("/api/v1/cms/user/:id/sessions", rbac.Require(http.MethodDelete, domain.PermissionUserManage))
 }
 
 func (h *UserHandler) Prepare() {
@@ -32,6 +47,153 @@ func (h *UserHandler) Prepare() {
 	h.SetLangVersion()
 }
 
+// Register
+// @Title Register
+// @Summary Create an account, it can log in once its email is verified with the token mailed to it
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse{data=domain.UserResponse}
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param body body domain.RegisterRequest true "account"
+// @Router /v1/cms/auth/register [post]
+func (h *UserHandler) Register() {
+	var request domain.RegisterRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if !h.ValidateRequest(request) {
+		return
+	}
+
+	result, err := h.UserUseCase.Register(h.Ctx, request)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// VerifyEmail
+// @Title VerifyEmail
+// @Summary Verify the email of an account with the token mailed to it, a token is used once
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse{data=domain.UserResponse}
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param body body domain.VerifyEmailRequest true "verification token"
+// @Router /v1/cms/auth/verify [post]
+func (h *UserHandler) VerifyEmail() {
+	var request domain.VerifyEmailRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if !h.ValidateRequest(request) {
+		return
+	}
+
+	result, err := h.UserUseCase.VerifyEmail(h.Ctx, request)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// ResendVerification
+// @Title ResendVerification
+// @Summary Mail a new token to verify the email, the answer does not tell whether the email is registered
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param body body domain.ResendVerificationRequest true "email"
+// @Router /v1/cms/auth/verify/resend [post]
+func (h *UserHandler) ResendVerification() {
+	var request domain.ResendVerificationRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if !h.ValidateRequest(request) {
+		return
+	}
+
+	if err := h.UserUseCase.ResendVerification(h.Ctx, request); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.verificationMailed"), nil)
+	return
+}
+
+// ForgotPassword
+// @Title ForgotPassword
+// @Summary Mail a token to reset the password, the answer does not tell whether the email is registered
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param body body domain.ForgotPasswordRequest true "email"
+// @Router /v1/cms/auth/password/forgot [post]
+func (h *UserHandler) ForgotPassword() {
+	var request domain.ForgotPasswordRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if !h.ValidateRequest(request) {
+		return
+	}
+
+	if err := h.UserUseCase.ForgotPassword(h.Ctx, request); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.passwordResetMailed"), nil)
+	return
+}
+
+// ResetPassword
+// @Title ResetPassword
+// @Summary Set a new password with the token mailed to the user, every session of the user is ended
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param body body domain.ResetPasswordRequest true "reset token and new password"
+// @Router /v1/cms/auth/password/reset [post]
+func (h *UserHandler) ResetPassword() {
+	var request domain.ResetPasswordRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if !h.ValidateRequest(request) {
+		return
+	}
+
+	if err := h.UserUseCase.ResetPassword(h.Ctx, request); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
 // RequestToken
 // @Title RequestToken
 // @Summary Generate JWT Token
@@ -55,8 +217,132 @@ func (h *UserHandler) RequestToken() {
 			h.ResponseErrorWithData(h.Ctx, http.StatusBadRequest, domain.InvalidEmailPassword, domain.ErrorCodeText(domain.InvalidEmailPassword, h.Locale.Lang), err, result)
 			return
 		}
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// RefreshToken
+// @Title RefreshToken
+// @Summary Trade a refresh token for a new access token and refresh token, a refresh token is used once
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse{data=domain.UserLoginResponse}
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 401 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param body body domain.RefreshTokenRequest true "refresh token"
+// @Router /v1/cms/user/refresh [post]
+func (h *UserHandler) RefreshToken() {
+	var request domain.RefreshTokenRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+	if !h.ValidateRequest(request) {
+		return
+	}
+
+	result, err := h.UserUseCase.Refresh(h.Ctx, request)
+	if err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), result)
+	return
+}
+
+// Logout
+// @Title Logout
+// @Summary End the session of the access token, and revoke the refresh token when it is sent
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 401 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param body body domain.LogoutRequest false "refresh token"
+// @Security ApiKeyAuth
+// @Router /v1/cms/user/logout [post]
+func (h *UserHandler) Logout() {
+	var request domain.LogoutRequest
+	if len(h.Ctx.Input.RequestBody) > 0 {
+		if err := h.BindJSON(&request); err != nil {
+			h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+			return
+		}
+	}
+
+	if err := h.UserUseCase.Logout(h.Ctx, request); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+// RevokeSessions
+// @Title RevokeSessions
+// @Summary End every session of a user, its tokens are refused from then on
+// @Produce json
+// @Tags User Auth
+// @Success 200 {object} swagger.BaseResponse
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param id path int true "user id"
+// @Security ApiKeyAuth
+// @Router /v1/cms/user/{id}/sessions [delete]
+func (h *UserHandler) RevokeSessions() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
 
-		h.ResponseError(h.Ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, h.Locale.Lang), err)
+	if err = h.UserUseCase.RevokeSessions(h.Ctx, pathParam); err != nil {
+		h.ResponseUseCaseError(err)
+		return
+	}
+	h.Ok(h.Ctx, h.Tr("message.success"), nil)
+	return
+}
+
+// AssignRoles
+// @Title AssignRoles
+// @Summary Replace the roles of a user
+// @Produce json
+// @Tags User Role
+// @Success 200 {object} swagger.BaseResponse
+// @Failure 400 {object} swagger.BadRequestResponse{errors=[]object,data=object}
+// @Failure 403 {object} swagger.UnauthorizedResponse{errors=[]object,data=object}
+// @Failure 500 {object} swagger.InternalServerErrorResponse{errors=[]object,data=object}
+// @Param Accept-Language header string false "lang"
+// @Param id path int true "user id"
+// @Param body body domain.AssignRolesRequest true "roles"
+// @Security ApiKeyAuth
+// @Router /v1/cms/user/{id}/roles [put]
+func (h *UserHandler) AssignRoles() {
+	pathParam, err := strconv.Atoi(h.Ctx.Input.Param(":id"))
+	if err != nil || pathParam < 1 {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.PathParamInvalidCode, domain.ErrorCodeText(domain.PathParamInvalidCode, h.Locale.Lang), err)
+		return
+	}
+
+	var request domain.AssignRolesRequest
+	if err := h.BindJSON(&request); err != nil {
+		h.ResponseError(h.Ctx, http.StatusBadRequest, domain.ApiValidationCodeError, domain.ErrorCodeText(domain.ApiValidationCodeError, h.Locale.Lang), err)
+		return
+	}
+
+	result, err := h.UserUseCase.AssignRoles(h.Ctx, pathParam, request)
+	if err != nil {
+		h.ResponseUseCaseError(err)
 		return
 	}
 	h.Ok(h.Ctx, h.Tr("message.success"), result)
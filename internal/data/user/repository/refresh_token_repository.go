@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) domain.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		DB: db,
+	}
+}
+
+func (rr refreshTokenRepository) Store(ctx context.Context, data domain.RefreshToken) error {
+	return rr.DB.WithContext(ctx).Omit("User").Create(&data).Error
+}
+
+func (rr refreshTokenRepository) FindByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var entity domain.RefreshToken
+	err := rr.DB.WithContext(ctx).First(&entity, "token_hash =?", hash).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+// MarkUsed only updates a token that is neither used nor revoked, so of two
+// concurrent refreshes with the same token only one wins.
+func (rr refreshTokenRepository) MarkUsed(ctx context.Context, id int, at time.Time) (bool, error) {
+	result := rr.DB.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (rr refreshTokenRepository) RevokeFamily(ctx context.Context, family string, at time.Time) error {
+	return rr.DB.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family = ? AND revoked_at IS NULL", family).
+		Update("revoked_at", at).Error
+}
+
+func (rr refreshTokenRepository) RevokeUser(ctx context.Context, userID int, at time.Time) error {
+	return rr.DB.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
@@ -3,25 +3,63 @@ package repository
 import (
 	"article-app/internal/domain"
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type userRepository struct {
-	DB *gorm.DB
+	db *gorm.DB
 }
 
 func NewUserRepository(db *gorm.DB) domain.UserRepository {
 	return &userRepository{
-		DB: db,
+		db: db,
 	}
 }
 
+func (ur userRepository) DB() *gorm.DB {
+	return ur.db
+}
+
+func (ur userRepository) Store(ctx context.Context, tx *gorm.DB, data domain.User) (int, error) {
+	err := tx.WithContext(ctx).Create(&data).Error
+	if err != nil {
+		return 0, err
+	}
+	return data.Id, nil
+}
+
 func (ur userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var entity domain.User
-	err := ur.DB.WithContext(ctx).First(&entity, "email =?", email).Error
+	err := ur.db.WithContext(ctx).Preload("Roles").First(&entity, "email =?", email).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+func (ur userRepository) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	var entity domain.User
+	err := ur.db.WithContext(ctx).Preload("Roles").First(&entity, "id =?", id).Error
 	if err != nil {
-		return nil, err
+		return nil, domain.RepositoryError(err)
 	}
 	return &entity, nil
 }
+
+func (ur userRepository) MarkEmailVerified(ctx context.Context, tx *gorm.DB, id int, at time.Time) error {
+	return tx.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", at).Error
+}
+
+func (ur userRepository) UpdatePassword(ctx context.Context, tx *gorm.DB, id int, hash string) error {
+	return tx.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", id).
+		Update("password", hash).Error
+}
+
+func (ur userRepository) ReplaceRoles(ctx context.Context, user *domain.User, roles []domain.Role) error {
+	return ur.db.WithContext(ctx).Model(user).Association("Roles").Replace(roles)
+}
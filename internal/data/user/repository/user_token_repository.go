@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"article-app/internal/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) domain.UserTokenRepository {
+	return &userTokenRepository{
+		db: db,
+	}
+}
+
+func (tr userTokenRepository) Store(ctx context.Context, tx *gorm.DB, data domain.UserToken) error {
+	return tx.WithContext(ctx).Omit("User").Create(&data).Error
+}
+
+func (tr userTokenRepository) FindByHash(ctx context.Context, purpose, hash string) (*domain.UserToken, error) {
+	var entity domain.UserToken
+	err := tr.db.WithContext(ctx).First(&entity, "purpose = ? AND token_hash = ?", purpose, hash).Error
+	if err != nil {
+		return nil, domain.RepositoryError(err)
+	}
+	return &entity, nil
+}
+
+// MarkUsed only updates a token that is not used yet, so of two concurrent
+// requests with the same token only one wins.
+func (tr userTokenRepository) MarkUsed(ctx context.Context, tx *gorm.DB, id int, at time.Time) (bool, error) {
+	result := tx.WithContext(ctx).Model(&domain.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (tr userTokenRepository) RevokeUser(ctx context.Context, tx *gorm.DB, userID int, purpose string, at time.Time) error {
+	return tx.WithContext(ctx).Model(&domain.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", at).Error
+}
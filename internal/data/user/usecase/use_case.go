@@ -3,27 +3,298 @@ package usecase
 import (
 	"article-app/internal/domain"
 	"context"
+	"errors"
+	"log"
+	"strings"
 	"time"
 
+	"article-app/pkg/helper"
 	"article-app/pkg/jwt"
+	"article-app/pkg/mailer"
 
 	beegoContext "github.com/beego/beego/v2/server/web/context"
+	"github.com/beego/i18n"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type userUseCase struct {
-	contextTimeout time.Duration
-	userRepository domain.UserRepository
-	jwtAuth        jwt.JWT
-	expireToken    int
+	contextTimeout         time.Duration
+	userRepository         domain.UserRepository
+	roleRepository         domain.RoleRepository
+	refreshTokenRepository domain.RefreshTokenRepository
+	userTokenRepository    domain.UserTokenRepository
+	jwtAuth                jwt.JWT
+	jwtIssuer              string
+	mailer                 mailer.Mailer
+	expireToken            int
+	expireRefreshToken     int
+	expireVerifyToken      int
+	expireResetToken       int
 }
 
-func NewUserUseCase(timeout time.Duration, ur domain.UserRepository, jwtAuth jwt.JWT, expireToken int) domain.UserUseCase {
+func NewUserUseCase(timeout time.Duration, ur domain.UserRepository, rr domain.RoleRepository, rtr domain.RefreshTokenRepository, utr domain.UserTokenRepository, jwtAuth jwt.JWT, jwtIssuer string, mail mailer.Mailer, expireToken, expireRefreshToken, expireVerifyToken, expireResetToken int) domain.UserUseCase {
 	return &userUseCase{
-		contextTimeout: timeout,
-		userRepository: ur,
-		jwtAuth:        jwtAuth,
-		expireToken:    expireToken,
+		contextTimeout:         timeout,
+		userRepository:         ur,
+		roleRepository:         rr,
+		refreshTokenRepository: rtr,
+		userTokenRepository:    utr,
+		jwtAuth:                jwtAuth,
+		jwtIssuer:              jwtIssuer,
+		mailer:                 mail,
+		expireToken:            expireToken,
+		expireRefreshToken:     expireRefreshToken,
+		expireVerifyToken:      expireVerifyToken,
+		expireResetToken:       expireResetToken,
+	}
+}
+
+// Register creates an account with the viewer role and mails it a token to
+// verify the email, the account cannot log in before.
+func (usc userUseCase) Register(beegoCtx *beegoContext.Context, body domain.RegisterRequest) (*domain.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	email := strings.ToLower(strings.TrimSpace(body.Email))
+	_, err := usc.userRepository.FindByEmail(ctx, email)
+	if err == nil {
+		return nil, domain.ErrEmailTaken
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	roles, err := usc.roleRepository.FindByNames(ctx, []string{domain.RoleViewer})
+	if err != nil {
+		return nil, err
+	}
+
+	user := domain.User{Email: email, Password: body.Password, Roles: roles}
+	var token string
+	// the account and its token are stored together, an account without a
+	// verification token is taken for one created before registration
+	err = usc.userRepository.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		if user.Id, err = usc.userRepository.Store(ctx, tx, user); err != nil {
+			return err
+		}
+		token, err = usc.storeToken(ctx, tx, user.Id, domain.UserTokenEmailVerification, usc.expireVerifyToken)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// a token that cannot be mailed is requested again with ResendVerification
+	err = usc.mailer.Send(ctx, usc.tokenMail(helper.GetLangVersion(beegoCtx), "verifyEmail", email, token, usc.expireVerifyToken))
+	if err != nil {
+		log.Println("error mailing verification token to user", user.Id, err)
+	}
+
+	res := user.ToUserResponse()
+	return &res, nil
+}
+
+// ResendVerification mails a new verification token to an unverified user,
+// the tokens mailed before are no longer valid. Like ForgotPassword it does
+// not tell whether the email is registered.
+func (usc userUseCase) ResendVerification(beegoCtx *beegoContext.Context, body domain.ResendVerificationRequest) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	user, err := usc.userRepository.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(body.Email)))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	go func(lang string) {
+		ctx, cancel := context.WithTimeout(context.Background(), usc.contextTimeout)
+		defer cancel()
+
+		if err := usc.mailToken(ctx, lang, user, domain.UserTokenEmailVerification, "verifyEmail", usc.expireVerifyToken); err != nil {
+			log.Println("error mailing verification token to user", user.Id, err)
+		}
+	}(helper.GetLangVersion(beegoCtx))
+	return nil
+}
+
+// mailToken replaces the unused tokens of purpose of user with a new one, and
+// mails it once stored. A mail that fails leaves a token nobody knows, the
+// user asks for another one.
+func (usc userUseCase) mailToken(ctx context.Context, lang string, user *domain.User, purpose, mail string, expire int) error {
+	var token string
+	err := usc.userRepository.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, err = usc.storeToken(ctx, tx, user.Id, purpose, expire)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return usc.mailer.Send(ctx, usc.tokenMail(lang, mail, user.Email, token, expire))
+}
+
+// storeToken replaces the unused tokens of purpose of the user with a new one
+// valid for expire seconds, and returns it.
+func (usc userUseCase) storeToken(ctx context.Context, tx *gorm.DB, userID int, purpose string, expire int) (string, error) {
+	token, err := helper.RandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	// only the last token mailed is valid
+	if err := usc.userTokenRepository.RevokeUser(ctx, tx, userID, purpose, now); err != nil {
+		return "", err
+	}
+	err = usc.userTokenRepository.Store(ctx, tx, domain.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: helper.HashToken(token),
+		ExpiresAt: now.Add(time.Duration(expire) * time.Second),
+	})
+	return token, err
+}
+
+// VerifyEmail marks the email of the owner of the token as verified, a token
+// is used once.
+func (usc userUseCase) VerifyEmail(beegoCtx *beegoContext.Context, body domain.VerifyEmailRequest) (*domain.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	found, err := usc.userTokenRepository.FindByHash(ctx, domain.UserTokenEmailVerification, helper.HashToken(body.Token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidVerifyToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if found.UsedAt != nil || now.After(found.ExpiresAt) {
+		return nil, domain.ErrInvalidVerifyToken
+	}
+
+	err = usc.userRepository.DB().Transaction(func(tx *gorm.DB) error {
+		claimed, err := usc.userTokenRepository.MarkUsed(ctx, tx, found.ID, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			// used by a concurrent request
+			return domain.ErrInvalidVerifyToken
+		}
+		return usc.userRepository.MarkEmailVerified(ctx, tx, found.UserID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := usc.userRepository.FindByID(ctx, found.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := user.ToUserResponse()
+	return &res, nil
+}
+
+// ForgotPassword mails a token to reset the password when the email is
+// registered. The answer is the same either way, and the token is mailed in
+// the background so the response time does not tell either.
+func (usc userUseCase) ForgotPassword(beegoCtx *beegoContext.Context, body domain.ForgotPasswordRequest) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	user, err := usc.userRepository.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(body.Email)))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	go usc.mailResetToken(helper.GetLangVersion(beegoCtx), user)
+	return nil
+}
+
+// mailResetToken replaces the reset tokens of user with a new one and mails
+// it. The request is already answered, so a failure is only logged.
+func (usc userUseCase) mailResetToken(lang string, user *domain.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), usc.contextTimeout)
+	defer cancel()
+
+	if err := usc.mailToken(ctx, lang, user, domain.UserTokenPasswordReset, "resetPassword", usc.expireResetToken); err != nil {
+		log.Println("error mailing password reset token to user", user.Id, err)
+	}
+}
+
+// ResetPassword sets the password of the owner of the token and ends every
+// session of the user, as the old password may be known to someone else. A
+// token is used once.
+func (usc userUseCase) ResetPassword(beegoCtx *beegoContext.Context, body domain.ResetPasswordRequest) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	found, err := usc.userTokenRepository.FindByHash(ctx, domain.UserTokenPasswordReset, helper.HashToken(body.Token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if found.UsedAt != nil || now.After(found.ExpiresAt) {
+		return domain.ErrInvalidResetToken
+	}
+
+	hash, err := domain.HashPassword(body.Password)
+	if err != nil {
+		return err
+	}
+
+	err = usc.userRepository.DB().Transaction(func(tx *gorm.DB) error {
+		claimed, err := usc.userTokenRepository.MarkUsed(ctx, tx, found.ID, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			// used by a concurrent request
+			return domain.ErrInvalidResetToken
+		}
+		if err = usc.userRepository.UpdatePassword(ctx, tx, found.UserID, hash); err != nil {
+			return err
+		}
+		// the token was mailed to the user, so the email is theirs
+		return usc.userRepository.MarkEmailVerified(ctx, tx, found.UserID, now)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = usc.refreshTokenRepository.RevokeUser(ctx, found.UserID, now); err != nil {
+		return err
+	}
+	return usc.jwtAuth.Ctx(ctx).DestroyIdentity(usc.jwtIssuer, found.UserID)
+}
+
+// tokenMail is the message carrying a user token, in the language of the
+// request. The texts are the <kind>Subject, <kind>Body and <kind>Expiry keys
+// of the mail section of conf/<lang>.ini.
+func (usc userUseCase) tokenMail(lang, kind, email, token string, expire int) mailer.Message {
+	hours := (expire + 3599) / 3600
+	return mailer.Message{
+		To:      []string{email},
+		Subject: i18n.Tr(lang, "mail."+kind+"Subject"),
+		Body:    i18n.Tr(lang, "mail."+kind+"Body") + "\n\n" + token + "\n\n" + i18n.Tr(lang, "mail."+kind+"Expiry", hours),
 	}
 }
 
@@ -32,6 +303,9 @@ func (usc userUseCase) Login(beegoCtx *beegoContext.Context, email, password str
 	defer cancel()
 
 	result, err := usc.userRepository.FindByEmail(ctx, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidEmailPassword
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -39,8 +313,141 @@ func (usc userUseCase) Login(beegoCtx *beegoContext.Context, email, password str
 	if err = bcrypt.CompareHashAndPassword([]byte(result.Password), []byte(password)); err != nil {
 		return nil, domain.ErrInvalidEmailPassword
 	}
+	if result.EmailVerifiedAt == nil {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	family, err := helper.RandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return usc.issueTokens(ctx, result, family)
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh
+// token of the same family. A refresh token is used once, replaying it revokes
+// its family and the current access token, as the token was likely stolen.
+func (usc userUseCase) Refresh(beegoCtx *beegoContext.Context, body domain.RefreshTokenRequest) (*domain.UserLoginResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	found, err := usc.refreshTokenRepository.FindByHash(ctx, helper.HashToken(body.RefreshToken))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if found.RevokedAt != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if found.UsedAt != nil {
+		return nil, usc.revokeFamily(ctx, found, now)
+	}
+	if now.After(found.ExpiresAt) {
+		return nil, domain.ErrExpiredRefreshToken
+	}
+
+	claimed, err := usc.refreshTokenRepository.MarkUsed(ctx, found.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// used by a concurrent refresh
+		return nil, usc.revokeFamily(ctx, found, now)
+	}
+
+	user, err := usc.userRepository.FindByID(ctx, found.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return usc.issueTokens(ctx, user, found.Family)
+}
+
+// Logout ends the session of the access token of the request, and revokes the
+// family of the refresh token when it is given.
+func (usc userUseCase) Logout(beegoCtx *beegoContext.Context, body domain.LogoutRequest) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	userID, err := helper.GetUserID(usc.jwtAuth, beegoCtx.Request)
+	if err != nil {
+		return err
+	}
+
+	if body.RefreshToken != "" {
+		found, err := usc.refreshTokenRepository.FindByHash(ctx, helper.HashToken(body.RefreshToken))
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		// the token of another user is left alone
+		if found != nil && found.UserID == userID {
+			if err = usc.refreshTokenRepository.RevokeFamily(ctx, found.Family, time.Now()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return usc.jwtAuth.Ctx(ctx).DestroyToken(beegoCtx.Request)
+}
+
+// RevokeSessions ends every session of the user, the access token and all the
+// refresh tokens it holds.
+func (usc userUseCase) RevokeSessions(beegoCtx *beegoContext.Context, id int) error {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	user, err := usc.userRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = usc.refreshTokenRepository.RevokeUser(ctx, user.Id, time.Now()); err != nil {
+		return err
+	}
+	return usc.jwtAuth.Ctx(ctx).DestroyIdentity(usc.jwtIssuer, user.Id)
+}
+
+// revokeFamily revokes the refresh tokens of the family of a replayed token
+// and logs the user out. The identity store keeps one access token per user,
+// not per family, so the access token is ended on purpose even when it was
+// issued by a newer login: a replay means a token leaked, and the owner logs
+// in again rather than leave the thief a window.
+func (usc userUseCase) revokeFamily(ctx context.Context, token *domain.RefreshToken, now time.Time) error {
+	if err := usc.refreshTokenRepository.RevokeFamily(ctx, token.Family, now); err != nil {
+		return err
+	}
+	if err := usc.jwtAuth.Ctx(ctx).DestroyIdentity(usc.jwtIssuer, token.UserID); err != nil {
+		return err
+	}
+	return domain.ErrRefreshTokenReused
+}
+
+// issueTokens generates the access token of user and a refresh token in family.
+func (usc userUseCase) issueTokens(ctx context.Context, user *domain.User, family string) (*domain.UserLoginResponse, error) {
+	token, err := usc.jwtAuth.Ctx(ctx).GenerateToken(jwt.Payload{"uid": user.Id, "email": user.Email, "roles": user.RoleNames()}, usc.jwtIssuer, usc.expireToken)
+	if err != nil {
+		return nil, err
+	}
 
-	token, err := usc.jwtAuth.Ctx(ctx).GenerateToken(jwt.Payload{"uid": result.Id, "email": result.Email}, beegoCtx.Request.Host, usc.expireToken)
+	refreshToken, err := helper.RandomToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshExpiredAt := time.Now().Add(time.Duration(usc.expireRefreshToken) * time.Second)
+	err = usc.refreshTokenRepository.Store(ctx, domain.RefreshToken{
+		UserID:    user.Id,
+		Family:    family,
+		TokenHash: helper.HashToken(refreshToken),
+		ExpiresAt: refreshExpiredAt,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -48,10 +455,38 @@ func (usc userUseCase) Login(beegoCtx *beegoContext.Context, email, password str
 	res := new(domain.UserLoginResponse)
 	res.Token = token.Token
 	res.ExpiredAt = token.ExpiredAt.String()
+	res.RefreshToken = refreshToken
+	res.RefreshExpiredAt = refreshExpiredAt.String()
 	res.User = domain.UserLogin{
-		Id:    int(result.Id),
-		Email: result.Email,
+		Id:    int(user.Id),
+		Email: user.Email,
+		Roles: user.RoleNames(),
 	}
 
 	return res, nil
 }
+
+func (usc userUseCase) AssignRoles(beegoCtx *beegoContext.Context, id int, body domain.AssignRolesRequest) (*domain.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(beegoCtx.Request.Context(), usc.contextTimeout)
+	defer cancel()
+
+	user, err := usc.userRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := usc.roleRepository.FindByNames(ctx, body.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(body.Roles) {
+		return nil, domain.ErrInvalidRole
+	}
+
+	if err = usc.userRepository.ReplaceRoles(ctx, user, roles); err != nil {
+		return nil, err
+	}
+
+	res := user.ToUserResponse()
+	return &res, nil
+}
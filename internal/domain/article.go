@@ -3,92 +3,245 @@ package domain
 import (
 	"article-app/pkg/database/paginator"
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/beego/beego/v2/core/validation"
 	beegoContext "github.com/beego/beego/v2/server/web/context"
 	"gorm.io/gorm"
 )
 
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusInReview  = "in_review"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+// ArticleStatusTransitions lists the statuses an article may move to from its current status.
+var ArticleStatusTransitions = map[string][]string{
+	ArticleStatusDraft:     {ArticleStatusInReview, ArticleStatusArchived},
+	ArticleStatusInReview:  {ArticleStatusPublished, ArticleStatusArchived},
+	ArticleStatusPublished: {ArticleStatusArchived},
+	ArticleStatusArchived:  {},
+}
+
+func IsValidArticleStatus(status string) bool {
+	_, ok := ArticleStatusTransitions[status]
+	return ok
+}
+
+// ArticleSort whitelists the fields the article list can be sorted by.
+var ArticleSort = paginator.SortSpec{
+	Fields: map[string]string{
+		"id":           "articles.id",
+		"title":        "articles.title",
+		"status":       "articles.status",
+		"created_at":   "articles.created_at",
+		"updated_at":   "articles.updated_at",
+		"published_at": "articles.published_at",
+		"publish_at":   "articles.publish_at",
+	},
+	Default:    "-created_at",
+	TieBreaker: "id",
+}
+
+// ArticleCursorSort whitelists the sort of the cursor mode of the article list.
+// Keyset pagination cannot seek past NULLs, so the nullable columns are left out.
+var ArticleCursorSort = paginator.SortSpec{
+	Fields: map[string]string{
+		"id":         "articles.id",
+		"title":      "articles.title",
+		"status":     "articles.status",
+		"created_at": "articles.created_at",
+		"updated_at": "articles.updated_at",
+	},
+	Default:    "-created_at",
+	TieBreaker: "id",
+}
+
 type Article struct {
-	ID        int            `gorm:"primarykey;autoIncrement:true"`
-	Author    string         `gorm:"type:text;column:author"`
-	Title     string         `gorm:"type:text;column:title"`
-	Body      string         `gorm:"type:text;column:body"`
-	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt time.Time      `gorm:"column:updated_at;autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;autoDeleteTime"`
+	ID          int            `gorm:"primarykey;autoIncrement:true"`
+	AuthorID    int            `gorm:"column:author_id;index"`
+	Author      User           `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Title       string         `gorm:"type:text;column:title"`
+	Slug        string         `gorm:"type:varchar(255);column:slug;uniqueIndex"`
+	Body        string         `gorm:"type:text;column:body"`
+	Status      string         `gorm:"type:varchar(20);column:status;index"`
+	PublishedAt *time.Time     `gorm:"column:published_at"`
+	PublishAt   *time.Time     `gorm:"column:publish_at;index"`
+	Version     int            `gorm:"column:version;not null;default:1"`
+	Tags        []Tag          `gorm:"many2many:article_tags"`
+	Categories  []Category     `gorm:"many2many:article_categories"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;autoDeleteTime"`
+	// Score is the relevance of a full text search, it is read only and only selected when searching.
+	Score float64 `gorm:"->;-:migration;column:score"`
 }
 
 func (u *User) TableName() string {
 	return "users"
 }
 
+// CanTransitionTo reports whether the workflow allows moving the article to status.
+func (r Article) CanTransitionTo(status string) bool {
+	for _, v := range ArticleStatusTransitions[r.Status] {
+		if v == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateArticleStoreRequest carries no author, the owner is always the
+// authenticated user taken from the jwt identity.
 type CreateArticleStoreRequest struct {
-	Author string `json:"author"`
-	Title  string `json:"title"`
-	Body   string `json:"body"`
+	Title       string `json:"title" valid:"Required;MaxSize(255)"`
+	Body        string `json:"body" valid:"Required"`
+	TagIDs      []int  `json:"tag_ids"`
+	CategoryIDs []int  `json:"category_ids"`
 }
 
-func (r CreateArticleStoreRequest) ToArticle() Article {
+func (r CreateArticleStoreRequest) Valid(v *validation.Validation) {
+	validBody(v, r.Body)
+}
+
+func (r CreateArticleStoreRequest) ToArticle(authorID int) Article {
 	return Article{
-		Body:   r.Body,
-		Title:  r.Title,
-		Author: r.Author,
+		Body:     r.Body,
+		Title:    r.Title,
+		AuthorID: authorID,
+		Status:   ArticleStatusDraft,
 	}
 }
 
+// UpdateArticleRequest leaves the tags and categories untouched when their ids
+// are omitted, an empty list removes them all.
 type UpdateArticleRequest struct {
-	ID     int    `json:"id"`
-	Author string `json:"author"`
-	Title  string `json:"title"`
-	Body   string `json:"body"`
+	ID          int    `json:"id"`
+	Title       string `json:"title" valid:"MaxSize(255)"`
+	Body        string `json:"body"`
+	TagIDs      []int  `json:"tag_ids"`
+	CategoryIDs []int  `json:"category_ids"`
+}
+
+// Valid keeps the title and body that are omitted, but rejects blank ones.
+func (r UpdateArticleRequest) Valid(v *validation.Validation) {
+	if r.Title != "" {
+		v.Required(r.Title, "Title.Required")
+	}
+	if r.Body != "" {
+		v.Required(r.Body, "Body.Required")
+	}
+	validBody(v, r.Body)
+}
+
+// MaxArticleBodyBytes is the size of the text column of the body, MySQL
+// counts it in bytes where MaxSize counts the characters.
+const MaxArticleBodyBytes = 65535
+
+func validBody(v *validation.Validation, body string) {
+	if len(body) > MaxArticleBodyBytes {
+		err := v.SetError("Body", fmt.Sprintf("must be at most %d bytes long", MaxArticleBodyBytes))
+		err.Name = "MaxBytes"
+		err.LimitValue = MaxArticleBodyBytes
+	}
 }
 
 func (r UpdateArticleRequest) ToArticle() Article {
 	return Article{
-		ID:     r.ID,
-		Body:   r.Body,
-		Title:  r.Title,
-		Author: r.Author,
+		ID:    r.ID,
+		Body:  r.Body,
+		Title: r.Title,
 	}
 }
 
 type GetArticleResponse struct {
-	ID     int    `json:"id"`
-	Author string `json:"author"`
-	Title  string `json:"title"`
-	Body   string `json:"body"`
+	ID          int                `json:"id"`
+	AuthorID    int                `json:"author_id"`
+	Author      UserResponse       `json:"author"`
+	Title       string             `json:"title"`
+	Slug        string             `json:"slug"`
+	Body        string             `json:"body"`
+	Status      string             `json:"status"`
+	PublishedAt *time.Time         `json:"published_at"`
+	PublishAt   *time.Time         `json:"publish_at"`
+	Version     int                `json:"version"`
+	Tags        []TagResponse      `json:"tags"`
+	Categories  []CategoryResponse `json:"categories"`
+	Score       *float64           `json:"score,omitempty"`
+	Snippet     string             `json:"snippet,omitempty"`
+}
+
+// ScheduleArticleRequest sets when an article in review goes live, a null
+// publish_at removes the article from the schedule.
+type ScheduleArticleRequest struct {
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 type GetArticlesFilter struct {
-	OrderBy string `json:"order_by"`
-	SortBy  string `json:"sort_by"`
-	Search  string `json:"search"`
-	Author  string `json:"author"`
+	Sort       paginator.Sort `json:"-"`
+	Search     string         `json:"search"`
+	SearchMode string         `json:"search_mode"`
+	AuthorID   int            `json:"author_id"`
+	Status     string         `json:"status"`
+	TagID      int            `json:"tag_id"`
+	CategoryID int            `json:"category_id"`
 }
 
 func (r Article) ToArticleResponse() GetArticleResponse {
 	return GetArticleResponse{
-		ID:     r.ID,
-		Author: r.Author,
-		Title:  r.Title,
-		Body:   r.Body,
+		ID:          r.ID,
+		AuthorID:    r.AuthorID,
+		Author:      r.Author.ToUserResponse(),
+		Title:       r.Title,
+		Slug:        r.Slug,
+		Body:        r.Body,
+		Status:      r.Status,
+		PublishedAt: r.PublishedAt,
+		PublishAt:   r.PublishAt,
+		Version:     r.Version,
+		Tags:        ToTagResponses(r.Tags),
+		Categories:  ToCategoryResponses(r.Categories),
 	}
 }
 
 type ArticleUseCase interface {
 	CreateArticle(beegoCtx *beegoContext.Context, data CreateArticleStoreRequest) (*GetArticleResponse, error)
-	GetArticles(beegoCtx *beegoContext.Context, page, limit, offset int, filter GetArticlesFilter) (result *paginator.Paginator, err error)
+	GetArticles(beegoCtx *beegoContext.Context, page, limit, offset int, filter GetArticlesFilter) (result *paginator.Page[GetArticleResponse], err error)
+	GetArticlesByCursor(beegoCtx *beegoContext.Context, cursor string, limit int, withTotal bool, filter GetArticlesFilter) (result *paginator.CursorPage[GetArticleResponse], err error)
 	GetArticleById(beegoCtx *beegoContext.Context, id int) (*GetArticleResponse, error)
-	UpdateArticle(beegoCtx *beegoContext.Context, body UpdateArticleRequest, id int) (*GetArticleResponse, error)
-	DeleteArticle(beegoCtx *beegoContext.Context, id int) error
+	GetArticleBySlug(beegoCtx *beegoContext.Context, slug string) (*GetArticleResponse, error)
+	GetPublishedArticleBySlug(beegoCtx *beegoContext.Context, slug string) (*GetArticleResponse, error)
+	UpdateArticle(beegoCtx *beegoContext.Context, body UpdateArticleRequest, id, version int) (*GetArticleResponse, error)
+	DeleteArticle(beegoCtx *beegoContext.Context, id, version int) error
+	SubmitArticle(beegoCtx *beegoContext.Context, id int) (*GetArticleResponse, error)
+	PublishArticle(beegoCtx *beegoContext.Context, id int) (*GetArticleResponse, error)
+	ArchiveArticle(beegoCtx *beegoContext.Context, id int) (*GetArticleResponse, error)
+	ScheduleArticle(beegoCtx *beegoContext.Context, body ScheduleArticleRequest, id int) (*GetArticleResponse, error)
+	GetScheduledArticles(beegoCtx *beegoContext.Context, page, limit, offset int) (result *paginator.Page[GetArticleResponse], err error)
+	PublishScheduledArticles(ctx context.Context, now time.Time) (int, error)
+	GetArticleRevisions(beegoCtx *beegoContext.Context, id, page, limit int) (result *paginator.Page[ArticleRevisionResponse], err error)
+	GetArticleRevisionDiff(beegoCtx *beegoContext.Context, id, from, to int) (*ArticleRevisionDiffResponse, error)
+	RestoreArticleRevision(beegoCtx *beegoContext.Context, id, revision int) (*GetArticleResponse, error)
 }
 
 type ArticleRepository interface {
 	Store(ctx context.Context, tx *gorm.DB, data Article) (int, error)
-	FetchWithFilterAndPagination(ctx context.Context, page, limit int, offset int, order string, fields, associate, filter []string, model interface{}, args ...interface{}) (*paginator.Paginator, error)
+	FetchWithFilterAndPagination(ctx context.Context, page, limit int, offset int, order string, fields, associate []string, filter paginator.Criteria, search *ArticleSearch) (*paginator.Page[Article], error)
+	FetchWithFilterAndCursor(ctx context.Context, cursor string, limit int, sort paginator.Sort, withTotal bool, fields, associate []string, filter paginator.Criteria, search *ArticleSearch) (*paginator.CursorPage[Article], error)
 	FindByID(ctx context.Context, id int) (*Article, error)
-	Update(ctx context.Context, body Article, id int) error
-	Delete(ctx context.Context, id int) error
+	FindBySlug(ctx context.Context, slug string) (*Article, error)
+	SlugTaken(ctx context.Context, tx *gorm.DB, slug string, articleID int) (bool, error)
+	RetireSlug(ctx context.Context, tx *gorm.DB, articleID int, oldSlug, newSlug string) error
+	Update(ctx context.Context, tx *gorm.DB, body Article, id, version int) error
+	Delete(ctx context.Context, id, version int) error
+	UpdateStatus(ctx context.Context, id, version int, status string, publishedAt *time.Time) error
+	UpdateSchedule(ctx context.Context, id, version int, publishAt *time.Time) error
+	ClaimScheduled(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]Article, error)
+	PublishScheduled(ctx context.Context, tx *gorm.DB, ids []int) error
+	ReplaceTags(ctx context.Context, tx *gorm.DB, article *Article, tags []Tag) error
+	ReplaceCategories(ctx context.Context, tx *gorm.DB, article *Article, categories []Category) error
 	DB() *gorm.DB
 }
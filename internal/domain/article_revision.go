@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"article-app/pkg/database/paginator"
+	"article-app/pkg/diff"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ArticleRevision is an immutable snapshot of a change to an article's title and body.
+type ArticleRevision struct {
+	ID           int       `gorm:"primarykey;autoIncrement:true"`
+	ArticleID    int       `gorm:"column:article_id;uniqueIndex:idx_article_revision"`
+	Revision     int       `gorm:"column:revision;uniqueIndex:idx_article_revision"`
+	EditorID     int       `gorm:"column:editor_id;index"`
+	Editor       User      `gorm:"foreignKey:EditorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	OldTitle     string    `gorm:"type:text;column:old_title"`
+	NewTitle     string    `gorm:"type:text;column:new_title"`
+	OldBody      string    `gorm:"type:text;column:old_body"`
+	NewBody      string    `gorm:"type:text;column:new_body"`
+	RestoredFrom *int      `gorm:"column:restored_from"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+type ArticleRevisionResponse struct {
+	ArticleID    int          `json:"article_id"`
+	Revision     int          `json:"revision"`
+	Editor       UserResponse `json:"editor"`
+	OldTitle     string       `json:"old_title"`
+	NewTitle     string       `json:"new_title"`
+	OldBody      string       `json:"old_body"`
+	NewBody      string       `json:"new_body"`
+	RestoredFrom *int         `json:"restored_from"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+func (r ArticleRevision) ToArticleRevisionResponse() ArticleRevisionResponse {
+	return ArticleRevisionResponse{
+		ArticleID:    r.ArticleID,
+		Revision:     r.Revision,
+		Editor:       r.Editor.ToUserResponse(),
+		OldTitle:     r.OldTitle,
+		NewTitle:     r.NewTitle,
+		OldBody:      r.OldBody,
+		NewBody:      r.NewBody,
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// ArticleRevisionDiffResponse compares the content left by two revisions.
+type ArticleRevisionDiffResponse struct {
+	ArticleID int         `json:"article_id"`
+	From      int         `json:"from"`
+	To        int         `json:"to"`
+	Title     []diff.Line `json:"title"`
+	Body      []diff.Line `json:"body"`
+}
+
+type ArticleRevisionRepository interface {
+	Store(ctx context.Context, tx *gorm.DB, data ArticleRevision) (int, error)
+	NextRevision(ctx context.Context, tx *gorm.DB, articleID int) (int, error)
+	FindByRevision(ctx context.Context, articleID, revision int) (*ArticleRevision, error)
+	FetchByArticle(ctx context.Context, articleID, page, limit int) (*paginator.Page[ArticleRevision], error)
+}
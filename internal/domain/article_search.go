@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"article-app/pkg/database/paginator"
+	"context"
+)
+
+const (
+	ArticleSearchNatural = "natural"
+	ArticleSearchBoolean = "boolean"
+)
+
+func IsValidArticleSearchMode(mode string) bool {
+	return mode == ArticleSearchNatural || mode == ArticleSearchBoolean
+}
+
+// ArticleSearchSort whitelists the sort of a searched article list, which is
+// ranked by relevance unless another sort is asked for.
+var ArticleSearchSort = paginator.SortSpec{
+	Fields: map[string]string{
+		"relevance":    "search.score",
+		"id":           "articles.id",
+		"title":        "articles.title",
+		"status":       "articles.status",
+		"created_at":   "articles.created_at",
+		"updated_at":   "articles.updated_at",
+		"published_at": "articles.published_at",
+		"publish_at":   "articles.publish_at",
+	},
+	Default:    "-relevance",
+	TieBreaker: "id",
+}
+
+// ArticleSearch is a full text query on the title and body of the articles.
+// In boolean mode the text may use the MySQL operators + - * and "phrases".
+type ArticleSearch struct {
+	Text string
+	Mode string
+}
+
+// ArticleSearcher finds the articles matching a full text query. MySQL uses
+// its FULLTEXT index, other databases the embedded index of SearchRepository.
+type ArticleSearcher interface {
+	// Join returns a JOIN clause and its values keeping only the articles that
+	// match search. The joined table is named search and has the article_id and
+	// the relevance score of every match, a higher score is more relevant.
+	Join(ctx context.Context, search ArticleSearch) (string, []interface{}, error)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// ArticleSlug keeps a slug an article used before its title changed, so old
+// links can be redirected to the current slug.
+type ArticleSlug struct {
+	ID        int       `gorm:"primarykey;autoIncrement:true"`
+	ArticleID int       `gorm:"column:article_id;index"`
+	Slug      string    `gorm:"type:varchar(255);column:slug;uniqueIndex"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"article-app/pkg/database/paginator"
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+// CategorySort whitelists the fields the category list can be sorted by.
+var CategorySort = paginator.SortSpec{
+	Fields: map[string]string{
+		"id":         "categories.id",
+		"name":       "categories.name",
+		"created_at": "categories.created_at",
+	},
+	Default:    "name",
+	TieBreaker: "id",
+}
+
+type Category struct {
+	ID          int       `gorm:"primarykey;autoIncrement:true"`
+	Name        string    `gorm:"type:varchar(100);column:name;unique"`
+	Description string    `gorm:"type:text;column:description"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+type CategoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r CategoryRequest) ToCategory() Category {
+	return Category{
+		Name:        r.Name,
+		Description: r.Description,
+	}
+}
+
+type CategoryResponse struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r Category) ToCategoryResponse() CategoryResponse {
+	return CategoryResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+	}
+}
+
+func ToCategoryResponses(categories []Category) []CategoryResponse {
+	res := make([]CategoryResponse, len(categories))
+	for k, v := range categories {
+		res[k] = v.ToCategoryResponse()
+	}
+	return res
+}
+
+type CategoryUseCase interface {
+	CreateCategory(beegoCtx *beegoContext.Context, body CategoryRequest) (*CategoryResponse, error)
+	GetCategories(beegoCtx *beegoContext.Context, page, limit int, sort paginator.Sort) (result *paginator.Page[CategoryResponse], err error)
+	GetCategoryById(beegoCtx *beegoContext.Context, id int) (*CategoryResponse, error)
+	UpdateCategory(beegoCtx *beegoContext.Context, body CategoryRequest, id int) (*CategoryResponse, error)
+	DeleteCategory(beegoCtx *beegoContext.Context, id int) error
+}
+
+type CategoryRepository interface {
+	Store(ctx context.Context, data Category) (int, error)
+	Fetch(ctx context.Context, page, limit int, sort paginator.Sort) (*paginator.Page[Category], error)
+	FindByID(ctx context.Context, id int) (*Category, error)
+	FindByIDs(ctx context.Context, ids []int) ([]Category, error)
+	FindByName(ctx context.Context, name string) (*Category, error)
+	Update(ctx context.Context, data Category, id int) error
+	Delete(ctx context.Context, id int) error
+}
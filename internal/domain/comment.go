@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"article-app/pkg/database/paginator"
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+)
+
+// Comment is a reader response to an article. Replies point to the comment they
+// answer through ParentID, only approved comments are shown to readers.
+type Comment struct {
+	ID        int       `gorm:"primarykey;autoIncrement:true"`
+	ArticleID int       `gorm:"column:article_id;index"`
+	Article   Article   `gorm:"foreignKey:ArticleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ParentID  *int      `gorm:"column:parent_id;index"`
+	Parent    *Comment  `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	AuthorID  int       `gorm:"column:author_id;index"`
+	Author    User      `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Body      string    `gorm:"type:text;column:body"`
+	Status    string    `gorm:"type:varchar(20);column:status;index"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// CreateCommentRequest answers the article, or the comment given by parent_id.
+type CreateCommentRequest struct {
+	ParentID *int   `json:"parent_id"`
+	Body     string `json:"body"`
+}
+
+func (r CreateCommentRequest) ToComment(articleID, authorID int) Comment {
+	return Comment{
+		ArticleID: articleID,
+		ParentID:  r.ParentID,
+		AuthorID:  authorID,
+		Body:      r.Body,
+		Status:    CommentStatusPending,
+	}
+}
+
+type CommentResponse struct {
+	ID        int               `json:"id"`
+	ArticleID int               `json:"article_id"`
+	ParentID  *int              `json:"parent_id"`
+	Author    UserResponse      `json:"author"`
+	Body      string            `json:"body"`
+	Status    string            `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+}
+
+func (r Comment) ToCommentResponse() CommentResponse {
+	return CommentResponse{
+		ID:        r.ID,
+		ArticleID: r.ArticleID,
+		ParentID:  r.ParentID,
+		Author:    r.Author.ToUserResponse(),
+		Body:      r.Body,
+		Status:    r.Status,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type CommentUseCase interface {
+	CreateComment(beegoCtx *beegoContext.Context, body CreateCommentRequest, articleID int) (*CommentResponse, error)
+	GetComments(beegoCtx *beegoContext.Context, articleID, page, limit int) (result *paginator.Page[CommentResponse], err error)
+	GetPendingComments(beegoCtx *beegoContext.Context, page, limit int) (result *paginator.Page[CommentResponse], err error)
+	ApproveComment(beegoCtx *beegoContext.Context, id int) (*CommentResponse, error)
+	RejectComment(beegoCtx *beegoContext.Context, id int) (*CommentResponse, error)
+	DeleteComment(beegoCtx *beegoContext.Context, id int) error
+}
+
+type CommentRepository interface {
+	Store(ctx context.Context, data Comment) (int, error)
+	FindByID(ctx context.Context, id int) (*Comment, error)
+	FetchByArticle(ctx context.Context, articleID, page, limit int) (*paginator.Page[Comment], error)
+	FindRepliesByArticle(ctx context.Context, articleID int) ([]Comment, error)
+	FetchPending(ctx context.Context, page, limit int) (*paginator.Page[Comment], error)
+	UpdateStatus(ctx context.Context, id int, status string) error
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/beego/i18n"
+)
+
+// ErrorCode declares the HTTP status a code is answered with and the key of
+// its message in the message section of the locale files. A handler may still
+// answer a code with a more precise status, like 422 for a request failing the
+// validation.
+type ErrorCode struct {
+	Code   string
+	Status int
+	Key    string
+}
+
+// ErrorCodes is the catalog of the codes of the api, every code is declared
+// here once.
+var ErrorCodes = []ErrorCode{
+	{Code: InvalidEmailPassword, Status: http.StatusBadRequest, Key: "errorInvalidEmailPassword"},
+	{Code: InvalidTokenCodeError, Status: http.StatusUnauthorized, Key: "errorInvalidToken"},
+	{Code: ExpiredTokenCodeError, Status: http.StatusUnauthorized, Key: "errorExpiredToken"},
+	{Code: MissingTokenCodeError, Status: http.StatusUnauthorized, Key: "errorMissingToken"},
+	{Code: AuthElseWhereCodeError, Status: http.StatusUnauthorized, Key: "errorAuthElseWhere"},
+	{Code: UnauthorizedCodeError, Status: http.StatusUnauthorized, Key: "errorUnauthorized"},
+	{Code: ResourceNotFoundCodeError, Status: http.StatusNotFound, Key: "errorResourceNotFound"},
+	{Code: ServerErrorCode, Status: http.StatusInternalServerError, Key: "errorServerError"},
+	{Code: ApiValidationCodeError, Status: http.StatusBadRequest, Key: "errorValidation"},
+	{Code: RequestTimeoutCodeError, Status: http.StatusRequestTimeout, Key: "errorRequestTimeout"},
+	{Code: ForbiddenCodeError, Status: http.StatusForbidden, Key: "errorForbidden"},
+	{Code: PermissionDeniedCodeError, Status: http.StatusForbidden, Key: "errorPermissionDenied"},
+	{Code: InvalidStatusCodeError, Status: http.StatusConflict, Key: "errorInvalidStatusTransition"},
+	{Code: PreconditionRequiredCode, Status: http.StatusPreconditionRequired, Key: "errorPreconditionRequired"},
+	{Code: PreconditionFailedCode, Status: http.StatusPreconditionFailed, Key: "errorPreconditionFailed"},
+	{Code: ConflictCodeError, Status: http.StatusConflict, Key: "errorConflict"},
+	{Code: CommentNotPendingCode, Status: http.StatusConflict, Key: "errorCommentNotPending"},
+	{Code: EmailTakenCodeError, Status: http.StatusConflict, Key: "errorEmailTaken"},
+	{Code: InvalidVerifyTokenCode, Status: http.StatusBadRequest, Key: "errorInvalidVerifyToken"},
+	{Code: EmailNotVerifiedCodeError, Status: http.StatusForbidden, Key: "errorEmailNotVerified"},
+	{Code: InvalidResetTokenCode, Status: http.StatusBadRequest, Key: "errorInvalidResetToken"},
+	{Code: QueryParamInvalidCode, Status: http.StatusBadRequest, Key: "errorQueryParamInvalid"},
+	{Code: PathParamInvalidCode, Status: http.StatusBadRequest, Key: "errorPathParamInvalid"},
+}
+
+var errorCodeIndex = func() map[string]ErrorCode {
+	index := make(map[string]ErrorCode, len(ErrorCodes))
+	for _, v := range ErrorCodes {
+		index[v.Code] = v
+	}
+	return index
+}()
+
+// LookupErrorCode returns the declaration of code.
+func LookupErrorCode(code string) (ErrorCode, bool) {
+	v, ok := errorCodeIndex[code]
+	return v, ok
+}
+
+// ErrorCodeStatus returns the HTTP status of code, 500 for a code that is not registered.
+func ErrorCodeStatus(code string) int {
+	if v, ok := LookupErrorCode(code); ok {
+		return v.Status
+	}
+	return http.StatusInternalServerError
+}
+
+type ErrorCodeResponse struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// ErrorCatalog lists every code with its message in locale.
+func ErrorCatalog(locale string) []ErrorCodeResponse {
+	result := make([]ErrorCodeResponse, 0, len(ErrorCodes))
+	for _, v := range ErrorCodes {
+		result = append(result, ErrorCodeResponse{
+			Code:    v.Code,
+			Status:  v.Status,
+			Message: ErrorCodeText(v.Code, locale),
+		})
+	}
+	return result
+}
+
+// CheckErrorCodes reports the codes that are declared twice, the mappings to
+// a code that is not declared and the messages missing from the locales.
+func CheckErrorCodes(locales ...string) []error {
+	var errs []error
+	seen := make(map[string]bool, len(ErrorCodes))
+	for _, v := range ErrorCodes {
+		if seen[v.Code] {
+			errs = append(errs, fmt.Errorf("error code %s is declared twice", v.Code))
+		}
+		seen[v.Code] = true
+	}
+	for _, v := range ErrorMappings {
+		if !seen[v.Code] {
+			errs = append(errs, fmt.Errorf("error %q is mapped to the undeclared code %s", v.Err, v.Code))
+		}
+	}
+	for _, locale := range locales {
+		if !i18n.IsExist(locale) {
+			errs = append(errs, fmt.Errorf("locale %s is not loaded", locale))
+			continue
+		}
+		for _, v := range ErrorCodes {
+			// Tr answers a missing key with the key itself
+			if i18n.Tr(locale, "message."+v.Key) == v.Key {
+				errs = append(errs, fmt.Errorf("locale %s has no message.%s for %s", locale, v.Key, v.Code))
+			}
+		}
+	}
+	return errs
+}
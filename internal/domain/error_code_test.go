@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/beego/i18n"
+)
+
+func TestCheckErrorCodes(t *testing.T) {
+	for locale, file := range map[string]string{"en-US": "../../conf/en.ini", "id-ID": "../../conf/id.ini"} {
+		if err := i18n.SetMessage(locale, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, err := range CheckErrorCodes("en-US", "id-ID") {
+		t.Error(err)
+	}
+}
+
+// TestErrorCodesDeclareEveryConstant parses the package so a code constant
+// added without its ErrorCodes entry fails here rather than answering 500.
+func TestErrorCodesDeclareEveryConstant(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := 0
+	for _, pkg := range pkgs {
+		ast.Inspect(pkg, func(n ast.Node) bool {
+			decl, ok := n.(*ast.GenDecl)
+			if !ok || decl.Tok != token.CONST {
+				return true
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, value := range spec.Values {
+					lit, ok := value.(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					code, err := strconv.Unquote(lit.Value)
+					if err != nil || !strings.HasPrefix(code, "ART-") {
+						continue
+					}
+					found++
+					if _, ok := LookupErrorCode(code); !ok {
+						t.Errorf("error code %s (%s) is not declared in ErrorCodes", code, spec.Names[i].Name)
+					}
+				}
+			}
+			return false
+		})
+	}
+	if found == 0 {
+		t.Fatal("no ART- constant found")
+	}
+}
@@ -1,10 +1,11 @@
 package domain
 
 import (
+	"context"
 	"errors"
-	"fmt"
 
 	"github.com/beego/i18n"
+	"gorm.io/gorm"
 )
 
 const (
@@ -18,6 +19,17 @@ const (
 	ServerErrorCode           = "ART-00008"
 	ApiValidationCodeError    = "ART-00009"
 	RequestTimeoutCodeError   = "ART-00010"
+	ForbiddenCodeError        = "ART-00011"
+	PermissionDeniedCodeError = "ART-00012"
+	InvalidStatusCodeError    = "ART-00013"
+	PreconditionRequiredCode  = "ART-00014"
+	PreconditionFailedCode    = "ART-00015"
+	ConflictCodeError         = "ART-00016"
+	CommentNotPendingCode     = "ART-00017"
+	EmailTakenCodeError       = "ART-00018"
+	InvalidVerifyTokenCode    = "ART-00019"
+	EmailNotVerifiedCodeError = "ART-00020"
+	InvalidResetTokenCode     = "ART-00021"
 
 	//Url Query & Param error
 	QueryParamInvalidCode = "ART-API-001"
@@ -25,33 +37,115 @@ const (
 )
 
 var (
+	//resource lookup, the repositories report a missing record with it
+	ErrNotFound = errors.New("resource not found")
+
 	//query param invalid
 	ErrQueryParamInvalid = errors.New("query param is invalid")
 
 	//login auth validation
 	ErrInvalidEmailPassword = errors.New("email Tidak Terdaftar atau kata sandi anda salah")
+	ErrEmailNotVerified     = errors.New("email has not been verified")
+
+	//registration
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrInvalidVerifyToken = errors.New("verification token is invalid or expired")
+
+	//password reset
+	ErrInvalidResetToken = errors.New("password reset token is invalid or expired")
+
+	//refresh token
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	ErrExpiredRefreshToken = errors.New("refresh token is expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used, its sessions are revoked")
+
+	//resource ownership validation
+	ErrForbidden = errors.New("you are not allowed to access this resource")
+
+	//role based access control
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidRole      = errors.New("role is not registered")
+
+	//article workflow
+	ErrInvalidStatusTransition = errors.New("article status transition is not allowed")
+	ErrPublishAtInPast         = errors.New("publish_at must be in the future")
+
+	//optimistic concurrency
+	ErrPreconditionRequired = errors.New("If-Match header is required")
+	ErrVersionMismatch      = errors.New("resource has been modified by another request")
+
+	//unique resource validation
+	ErrConflict = errors.New("resource already exists")
+
+	//article taxonomy
+	ErrInvalidTag      = errors.New("tag is not registered")
+	ErrInvalidCategory = errors.New("category is not registered")
+
+	//comment moderation
+	ErrInvalidParentComment = errors.New("parent comment does not belong to the article")
+	ErrCommentNotPending    = errors.New("comment has already been moderated")
 )
 
+// ErrorMapping ties a sentinel error to the code it is answered with.
+type ErrorMapping struct {
+	Err  error
+	Code string
+}
+
+// ErrorMappings translates the errors of the use cases to responses, the first
+// mapping matching the error with errors.Is wins.
+var ErrorMappings = []ErrorMapping{
+	{Err: ErrNotFound, Code: ResourceNotFoundCodeError},
+	{Err: ErrConflict, Code: ConflictCodeError},
+	{Err: ErrForbidden, Code: ForbiddenCodeError},
+	{Err: ErrPermissionDenied, Code: PermissionDeniedCodeError},
+	{Err: ErrInvalidEmailPassword, Code: InvalidEmailPassword},
+	{Err: ErrEmailNotVerified, Code: EmailNotVerifiedCodeError},
+	{Err: ErrEmailTaken, Code: EmailTakenCodeError},
+	{Err: ErrInvalidVerifyToken, Code: InvalidVerifyTokenCode},
+	{Err: ErrInvalidResetToken, Code: InvalidResetTokenCode},
+	{Err: ErrInvalidRefreshToken, Code: InvalidTokenCodeError},
+	{Err: ErrExpiredRefreshToken, Code: ExpiredTokenCodeError},
+	{Err: ErrRefreshTokenReused, Code: InvalidTokenCodeError},
+	{Err: ErrInvalidStatusTransition, Code: InvalidStatusCodeError},
+	{Err: ErrPreconditionRequired, Code: PreconditionRequiredCode},
+	{Err: ErrVersionMismatch, Code: PreconditionFailedCode},
+	{Err: ErrCommentNotPending, Code: CommentNotPendingCode},
+	{Err: ErrQueryParamInvalid, Code: QueryParamInvalidCode},
+	{Err: ErrPublishAtInPast, Code: ApiValidationCodeError},
+	{Err: ErrInvalidRole, Code: ApiValidationCodeError},
+	{Err: ErrInvalidTag, Code: ApiValidationCodeError},
+	{Err: ErrInvalidCategory, Code: ApiValidationCodeError},
+	{Err: ErrInvalidParentComment, Code: ApiValidationCodeError},
+	{Err: context.DeadlineExceeded, Code: RequestTimeoutCodeError},
+}
+
+// ErrorStatus returns the HTTP status and the code err is answered with, an
+// error without mapping is a server error.
+func ErrorStatus(err error) (int, string) {
+	for _, v := range ErrorMappings {
+		if errors.Is(err, v.Err) {
+			return ErrorCodeStatus(v.Code), v.Code
+		}
+	}
+	return ErrorCodeStatus(ServerErrorCode), ServerErrorCode
+}
+
+// RepositoryError turns the errors of gorm into the sentinel errors of the
+// domain, the repositories return their errors through it.
+func RepositoryError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ErrorCodeText returns the message of code in locale, empty for a code that
+// is not registered.
 func ErrorCodeText(code, locale string, args ...interface{}) string {
-	fmt.Println(locale, "isi locale")
-	switch code {
-	case InvalidEmailPassword:
-		return i18n.Tr(locale, "message.errorInvalidEmailPassword", args)
-	case QueryParamInvalidCode:
-		return i18n.Tr(locale, "message.errorQueryParamInvalid", args)
-	case PathParamInvalidCode:
-		return i18n.Tr(locale, "message.errorPathParamInvalid", args)
-	case ApiValidationCodeError:
-		return i18n.Tr(locale, "message.errorValidation", args)
-	case ResourceNotFoundCodeError:
-		return i18n.Tr(locale, "message.errorResourceNotFound", args)
-	case ServerErrorCode:
-		return i18n.Tr(locale, "message.errorServerError", args)
-	case RequestTimeoutCodeError:
-		return i18n.Tr(locale, "message.errorRequestTimeout", args)
-	case MissingTokenCodeError:
-		return i18n.Tr(locale, "message.errorMissingToken", args)
-	default:
+	v, ok := LookupErrorCode(code)
+	if !ok {
 		return ""
 	}
+	return i18n.Tr(locale, "message."+v.Key, args...)
 }
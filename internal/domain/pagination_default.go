@@ -1,6 +1,10 @@
 package domain
 
-import "strconv"
+import (
+	"article-app/pkg/database/paginator"
+	"strconv"
+	"strings"
+)
 
 const (
 	DEFAULT_PAGESIZE = 10
@@ -48,3 +52,19 @@ func PaginationQueryParamValidation(pageSizeStr, pageStr string) (int, int, erro
 
 	return pageSizeDefault, pageDefault, nil
 }
+
+// SortQueryParamValidation parses the sort query param against spec. The legacy
+// sort_by and order_by params are still honoured when sort is empty.
+func SortQueryParamValidation(spec paginator.SortSpec, sort, sortBy, orderBy string) (paginator.Sort, error) {
+	if sort == "" && sortBy != "" {
+		switch strings.ToLower(orderBy) {
+		case "", "asc":
+			sort = sortBy
+		case "desc":
+			sort = "-" + sortBy
+		default:
+			return nil, ErrQueryParamInvalid
+		}
+	}
+	return spec.Parse(sort)
+}
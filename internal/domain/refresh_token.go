@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshToken is a long lived, single use token traded for a new access
+// token. Only the sha256 of the token is stored. The tokens issued from one
+// login share a family, replaying a used token revokes the whole family.
+type RefreshToken struct {
+	ID        int        `gorm:"primarykey;autoIncrement:true"`
+	UserID    int        `gorm:"column:user_id;index"`
+	User      User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Family    string     `gorm:"type:varchar(64);column:family;index"`
+	TokenHash string     `gorm:"type:char(64);column:token_hash;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;index"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" valid:"Required"`
+}
+
+// LogoutRequest revokes the given refresh token with the access token, the
+// refresh token is optional.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshTokenRepository interface {
+	Store(ctx context.Context, data RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	// MarkUsed reports false when the token was used or revoked meanwhile.
+	MarkUsed(ctx context.Context, id int, at time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, family string, at time.Time) error
+	RevokeUser(ctx context.Context, userID int, at time.Time) error
+}
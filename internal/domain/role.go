@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleAuthor = "author"
+	RoleViewer = "viewer"
+)
+
+const (
+	PermissionArticleRead     = "article.read"
+	PermissionArticleCreate   = "article.create"
+	PermissionArticleUpdate   = "article.update"
+	PermissionArticleDelete   = "article.delete"
+	PermissionArticlePublish  = "article.publish"
+	PermissionUserManage      = "user.manage"
+	PermissionTaxonomyManage  = "taxonomy.manage"
+	PermissionCommentCreate   = "comment.create"
+	PermissionCommentModerate = "comment.moderate"
+)
+
+// DefaultRolePermissions is the role matrix seeded into the database.
+// Changing a role afterwards is done on the roles tables, not here.
+var DefaultRolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermissionArticleRead,
+		PermissionArticleCreate,
+		PermissionArticleUpdate,
+		PermissionArticleDelete,
+		PermissionArticlePublish,
+		PermissionUserManage,
+		PermissionTaxonomyManage,
+		PermissionCommentCreate,
+		PermissionCommentModerate,
+	},
+	RoleEditor: {
+		PermissionArticleRead,
+		PermissionArticleCreate,
+		PermissionArticleUpdate,
+		PermissionArticleDelete,
+		PermissionArticlePublish,
+		PermissionTaxonomyManage,
+		PermissionCommentCreate,
+		PermissionCommentModerate,
+	},
+	RoleAuthor: {
+		PermissionArticleRead,
+		PermissionArticleCreate,
+		PermissionArticleUpdate,
+		PermissionArticleDelete,
+		PermissionCommentCreate,
+	},
+	RoleViewer: {
+		PermissionArticleRead,
+		PermissionCommentCreate,
+	},
+}
+
+type Role struct {
+	Id          int          `gorm:"primarykey;autoIncrement:true"`
+	Name        string       `gorm:"type:varchar(50);column:name;unique"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+}
+
+type Permission struct {
+	Id   int    `gorm:"primarykey;autoIncrement:true"`
+	Name string `gorm:"type:varchar(100);column:name;unique"`
+}
+
+type RoleRepository interface {
+	FindByNames(ctx context.Context, names []string) ([]Role, error)
+	FindPermissionsByRoles(ctx context.Context, roles []string) ([]string, error)
+}
+
+type AssignRolesRequest struct {
+	Roles []string `json:"roles"`
+}
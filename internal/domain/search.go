@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"article-app/pkg/database/paginator"
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+// Facets of the search index, the values of a facet are ids.
+const (
+	SearchFacetAuthor = "author"
+	SearchFacetTag    = "tag"
+	SearchFacetStatus = "status"
+)
+
+// SearchQuery is a search of the article index. Mode is ArticleSearchNatural
+// or ArticleSearchBoolean, the zero filters match every article.
+type SearchQuery struct {
+	Text     string
+	Mode     string
+	AuthorID int
+	TagID    int
+	Status   string
+}
+
+// SearchDocument is what the search index keeps of an article, enough to
+// list the hits without reading the database.
+type SearchDocument struct {
+	ID        int           `json:"id"`
+	Title     string        `json:"title"`
+	Slug      string        `json:"slug"`
+	Body      string        `json:"body"`
+	Status    string        `json:"status"`
+	Author    UserResponse  `json:"author"`
+	Tags      []TagResponse `json:"tags"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func (r Article) ToSearchDocument() SearchDocument {
+	return SearchDocument{
+		ID:        r.ID,
+		Title:     r.Title,
+		Slug:      r.Slug,
+		Body:      r.Body,
+		Status:    r.Status,
+		Author:    r.Author.ToUserResponse(),
+		Tags:      ToTagResponses(r.Tags),
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+type SearchHit struct {
+	SearchDocument
+	Score float64
+}
+
+// SearchFacet is the number of hits of an author or a tag.
+type SearchFacet struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type SearchFacets struct {
+	Author []SearchFacet `json:"author"`
+	Tag    []SearchFacet `json:"tag"`
+}
+
+// SearchResult is a page of hits, the facets count every hit.
+type SearchResult struct {
+	Total  int64
+	Hits   []SearchHit
+	Facets SearchFacets
+}
+
+type SearchHitResponse struct {
+	ID        int           `json:"id"`
+	Title     string        `json:"title"`
+	Slug      string        `json:"slug"`
+	Status    string        `json:"status"`
+	Author    UserResponse  `json:"author"`
+	Tags      []TagResponse `json:"tags"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Score     float64       `json:"score"`
+	Snippet   string        `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Hits   []SearchHitResponse `json:"hits"`
+	Facets SearchFacets        `json:"facets"`
+}
+
+type SearchUseCase interface {
+	Search(beegoCtx *beegoContext.Context, query SearchQuery, page, limit int) (*paginator.Page[SearchHitResponse], *SearchFacets, error)
+}
+
+// SearchRepository is the article index, it is kept up to date by the article
+// use case and saved to disk by Flush.
+type SearchRepository interface {
+	Index(ctx context.Context, article Article) error
+	Remove(ctx context.Context, id int) error
+	Search(ctx context.Context, query SearchQuery, offset, limit int) (*SearchResult, error)
+	// Matcher reports whether a word of a text is a match of query, to highlight it.
+	Matcher(query SearchQuery) func(word string) bool
+	// Rebuild indexes every article of the database again.
+	Rebuild(ctx context.Context) error
+	// Sync indexes again the articles whose version differs from the index
+	// and removes the deleted ones.
+	Sync(ctx context.Context) error
+	// Flush saves the index when it changed since the last save.
+	Flush() error
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"article-app/pkg/database/paginator"
+	"context"
+	"time"
+
+	beegoContext "github.com/beego/beego/v2/server/web/context"
+)
+
+// TagSort whitelists the fields the tag list can be sorted by.
+var TagSort = paginator.SortSpec{
+	Fields: map[string]string{
+		"id":         "tags.id",
+		"name":       "tags.name",
+		"created_at": "tags.created_at",
+	},
+	Default:    "name",
+	TieBreaker: "id",
+}
+
+type Tag struct {
+	ID        int       `gorm:"primarykey;autoIncrement:true"`
+	Name      string    `gorm:"type:varchar(100);column:name;unique"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+func (r TagRequest) ToTag() Tag {
+	return Tag{
+		Name: r.Name,
+	}
+}
+
+type TagResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r Tag) ToTagResponse() TagResponse {
+	return TagResponse{
+		ID:   r.ID,
+		Name: r.Name,
+	}
+}
+
+func ToTagResponses(tags []Tag) []TagResponse {
+	res := make([]TagResponse, len(tags))
+	for k, v := range tags {
+		res[k] = v.ToTagResponse()
+	}
+	return res
+}
+
+type TagUseCase interface {
+	CreateTag(beegoCtx *beegoContext.Context, body TagRequest) (*TagResponse, error)
+	GetTags(beegoCtx *beegoContext.Context, page, limit int, sort paginator.Sort) (result *paginator.Page[TagResponse], err error)
+	GetTagById(beegoCtx *beegoContext.Context, id int) (*TagResponse, error)
+	UpdateTag(beegoCtx *beegoContext.Context, body TagRequest, id int) (*TagResponse, error)
+	DeleteTag(beegoCtx *beegoContext.Context, id int) error
+}
+
+type TagRepository interface {
+	Store(ctx context.Context, data Tag) (int, error)
+	Fetch(ctx context.Context, page, limit int, sort paginator.Sort) (*paginator.Page[Tag], error)
+	FindByID(ctx context.Context, id int) (*Tag, error)
+	FindByIDs(ctx context.Context, ids []int) ([]Tag, error)
+	FindByName(ctx context.Context, name string) (*Tag, error)
+	Update(ctx context.Context, data Tag, id int) error
+	Delete(ctx context.Context, id int) error
+}
@@ -2,8 +2,11 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"time"
+	"unicode"
 
+	"github.com/beego/beego/v2/core/validation"
 	beegoContext "github.com/beego/beego/v2/server/web/context"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -13,9 +16,14 @@ type User struct {
 	Id        int            `gorm:"primarykey;autoIncrement:true"`
 	Email     string         `gorm:"type:varchar(100);column:email;unique"`
 	Password  string         `gorm:"type:varchar(200);column:password"`
+	Roles     []Role         `gorm:"many2many:user_roles"`
 	CreatedAt time.Time      `gorm:"column:created_at"`
 	UpdatedAt time.Time      `gorm:"column:updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at"`
+
+	// EmailVerifiedAt is set once the user proved they own the email, a user
+	// cannot log in before
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at"`
 }
 
 // func (u *User) TableName() string {
@@ -23,29 +31,107 @@ type User struct {
 // }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
-	if bytes, err := bcrypt.GenerateFromPassword([]byte(u.Password), 10); err != nil {
-		return err
-	} else {
-		u.Password = string(bytes)
+	u.Password, err = HashPassword(u.Password)
+	return err
+}
+
+// HashPassword returns the bcrypt hash of password stored in users.password.
+func HashPassword(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// RoleNames returns the names of the roles loaded on the user.
+func (u User) RoleNames() []string {
+	names := make([]string, len(u.Roles))
+	for k, v := range u.Roles {
+		names[k] = v.Name
 	}
-	return nil
+	return names
 }
 
 type UserUseCase interface {
+	Register(beegoCtx *beegoContext.Context, body RegisterRequest) (*UserResponse, error)
+	VerifyEmail(beegoCtx *beegoContext.Context, body VerifyEmailRequest) (*UserResponse, error)
+	ResendVerification(beegoCtx *beegoContext.Context, body ResendVerificationRequest) error
+	ForgotPassword(beegoCtx *beegoContext.Context, body ForgotPasswordRequest) error
+	ResetPassword(beegoCtx *beegoContext.Context, body ResetPasswordRequest) error
 	Login(beegoCtx *beegoContext.Context, email, password string) (interface{}, error)
+	Refresh(beegoCtx *beegoContext.Context, body RefreshTokenRequest) (*UserLoginResponse, error)
+	Logout(beegoCtx *beegoContext.Context, body LogoutRequest) error
+	RevokeSessions(beegoCtx *beegoContext.Context, id int) error
+	AssignRoles(beegoCtx *beegoContext.Context, id int, body AssignRolesRequest) (*UserResponse, error)
 }
 
 type UserRepository interface {
+	Store(ctx context.Context, tx *gorm.DB, data User) (int, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByID(ctx context.Context, id int) (*User, error)
+	MarkEmailVerified(ctx context.Context, tx *gorm.DB, id int, at time.Time) error
+	UpdatePassword(ctx context.Context, tx *gorm.DB, id int, hash string) error
+	ReplaceRoles(ctx context.Context, user *User, roles []Role) error
+	DB() *gorm.DB
+}
+
+// RegisterRequest creates an account, the password must mix upper and lower
+// case letters and digits.
+type RegisterRequest struct {
+	Email    string `json:"email" valid:"Required;Email;MaxSize(100)"`
+	Password string `json:"password" valid:"Required;MinSize(8);MaxSize(72)"`
+}
+
+func (r RegisterRequest) Valid(v *validation.Validation) {
+	validPassword(v, r.Password, r.Email)
+}
+
+// validPassword checks the password policy, an empty email is not compared.
+func validPassword(v *validation.Validation, password, email string) {
+	var upper, lower, digit bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsDigit(c):
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		v.AddError("Password.PasswordPolicy.", "must contain an uppercase letter, a lowercase letter and a digit")
+	}
+	if email != "" && strings.EqualFold(password, email) {
+		v.AddError("Password.PasswordEmail.", "must not be the email")
+	}
 }
 
 type UserLogin struct {
-	Id    int    `json:"id"`
-	Email string `json:"email"`
+	Id    int      `json:"id"`
+	Email string   `json:"email"`
+	Roles []string `json:"roles"`
+}
+
+type UserResponse struct {
+	Id    int      `json:"id"`
+	Email string   `json:"email"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func (u User) ToUserResponse() UserResponse {
+	return UserResponse{
+		Id:    u.Id,
+		Email: u.Email,
+		Roles: u.RoleNames(),
+	}
 }
 
 type UserLoginResponse struct {
-	Token     string    `json:"token"`
-	ExpiredAt string    `json:"expired_at"`
-	User      UserLogin `json:"user"`
+	Token            string    `json:"token"`
+	ExpiredAt        string    `json:"expired_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiredAt string    `json:"refresh_expired_at"`
+	User             UserLogin `json:"user"`
 }
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/beego/beego/v2/core/validation"
+	"gorm.io/gorm"
+)
+
+// purposes of the user tokens
+const (
+	UserTokenEmailVerification = "email_verification"
+	UserTokenPasswordReset     = "password_reset"
+)
+
+// UserToken is a single use token mailed to a user to prove they own their
+// email, to verify it or to reset the password. Only the sha256 of the token
+// is stored.
+type UserToken struct {
+	ID        int        `gorm:"primarykey;autoIncrement:true"`
+	UserID    int        `gorm:"column:user_id;index"`
+	User      User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Purpose   string     `gorm:"type:varchar(32);column:purpose"`
+	TokenHash string     `gorm:"type:char(64);column:token_hash;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" valid:"Required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" valid:"Required;Email;MaxSize(100)"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" valid:"Required;Email;MaxSize(100)"`
+}
+
+// ResetPasswordRequest sets the password of the owner of the token, the
+// password follows the policy of RegisterRequest.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" valid:"Required"`
+	Password string `json:"password" valid:"Required;MinSize(8);MaxSize(72)"`
+}
+
+func (r ResetPasswordRequest) Valid(v *validation.Validation) {
+	validPassword(v, r.Password, "")
+}
+
+type UserTokenRepository interface {
+	Store(ctx context.Context, tx *gorm.DB, data UserToken) error
+	FindByHash(ctx context.Context, purpose, hash string) (*UserToken, error)
+	// MarkUsed reports false when the token was used meanwhile.
+	MarkUsed(ctx context.Context, tx *gorm.DB, id int, at time.Time) (bool, error)
+	// RevokeUser marks the unused tokens of purpose of the user as used.
+	RevokeUser(ctx context.Context, tx *gorm.DB, userID int, purpose string, at time.Time) error
+}
@@ -25,12 +25,36 @@ func NewJwtMiddleware() *JwtConfig {
 		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/cms/auth/register") {
 			return true
 		}
+		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/cms/auth/verify") {
+			return true
+		}
+		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/cms/auth/verify/resend") {
+			return true
+		}
+		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/cms/auth/password/forgot") {
+			return true
+		}
+		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/cms/auth/password/reset") {
+			return true
+		}
 		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/cms/user/login") {
 			return true
 		}
+		// the access token may have expired, the refresh token authenticates
+		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/cms/user/refresh") {
+			return true
+		}
 		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/employee/auth/login") {
 			return true
 		}
+		// catalog of the error codes
+		if strings.EqualFold(ctx.Request.URL.Path, "/api/v1/errors") {
+			return true
+		}
+		// public article pages
+		if strings.HasPrefix(strings.ToLower(ctx.Request.URL.Path), "/api/v1/article/") {
+			return true
+		}
 		return false
 	}}
 }
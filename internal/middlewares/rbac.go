@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/helper"
+	"article-app/pkg/jwt"
+	"article-app/pkg/response"
+	"net/http"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/beego/beego/v2/server/web/context"
+)
+
+type RbacConfig struct {
+	Skipper        Skipper
+	JwtAuth        jwt.JWT
+	RoleRepository domain.RoleRepository
+	response.ApiResponse
+}
+
+func NewRbacMiddleware(jwtAuth jwt.JWT, roleRepository domain.RoleRepository) *RbacConfig {
+	return &RbacConfig{
+		Skipper:        DefaultSkipper,
+		JwtAuth:        jwtAuth,
+		RoleRepository: roleRepository,
+	}
+}
+
+// Require returns a filter chain which only lets requests with the given method
+// through when the roles in the token grant every listed permission.
+// It must be inserted after JwtMiddleware so the payload is already verified.
+//
+//	beego.Router("/api/v1/cms/article", handler, "post:CreateArticle")
+//	beego.InsertFilterChain("/api/v1/cms/article", rbac.Require(http.MethodPost, domain.PermissionArticleCreate))
+func (r *RbacConfig) Require(method string, permissions ...string) beego.FilterChain {
+	return func(next beego.FilterFunc) beego.FilterFunc {
+		return func(ctx *context.Context) {
+			if r.Skipper(ctx) || !strings.EqualFold(ctx.Request.Method, method) {
+				next(ctx)
+				return
+			}
+
+			granted, err := r.RoleRepository.FindPermissionsByRoles(ctx.Request.Context(), helper.GetRoles(r.JwtAuth, ctx.Request))
+			if err != nil {
+				r.ResponseError(ctx, http.StatusInternalServerError, domain.ServerErrorCode, domain.ErrorCodeText(domain.ServerErrorCode, helper.GetLangVersion(ctx)), err)
+				return
+			}
+
+			for _, permission := range permissions {
+				if !contains(granted, permission) {
+					r.ResponseError(ctx, http.StatusForbidden, domain.PermissionDeniedCodeError, domain.ErrorCodeText(domain.PermissionDeniedCodeError, helper.GetLangVersion(ctx)), domain.ErrPermissionDenied)
+					return
+				}
+			}
+			next(ctx)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
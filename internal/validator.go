@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"article-app/pkg/response"
+	"reflect"
+	"strings"
+
+	"github.com/beego/beego/v2/core/validation"
+	"github.com/beego/i18n"
+)
+
+// Validate checks request against its valid tags, e.g. `valid:"Required;MaxSize(255)"`,
+// see github.com/beego/beego/v2/core/validation. A request may add its own
+// checks with a Valid(*validation.Validation) method.
+//
+// Every failed rule gives one response.Errors named after the json field, with
+// the message of the rule in lang. The messages are the validation section of
+// conf/<lang>.ini, a rule without a translation keeps the English message.
+// The result is empty when the request is valid.
+func Validate(lang string, request interface{}) ([]response.Errors, error) {
+	valid := validation.Validation{}
+	if ok, err := valid.Valid(request); err != nil || ok {
+		return nil, err
+	}
+
+	errs := make([]response.Errors, len(valid.Errors))
+	for k, v := range valid.Errors {
+		errs[k] = response.Errors{
+			Field:       jsonName(request, v.Field),
+			Description: validationMessage(lang, v),
+		}
+	}
+	return errs, nil
+}
+
+// validationMessage translates the rule of err, its limits are the arguments
+// of the message, e.g. "validation.MaxSize = must be at most %d characters".
+func validationMessage(lang string, err *validation.Error) string {
+	var args []interface{}
+	switch limit := err.LimitValue.(type) {
+	case nil:
+	case []int:
+		for _, v := range limit {
+			args = append(args, v)
+		}
+	default:
+		args = append(args, limit)
+	}
+
+	// Tr answers a missing key with the key without its section
+	if !i18n.IsExist(lang) || i18n.Tr(lang, "validation."+err.Name) == err.Name {
+		return strings.TrimSpace(strings.TrimPrefix(err.Message, err.Field))
+	}
+	return i18n.Tr(lang, "validation."+err.Name, args...)
+}
+
+// jsonName returns the json name of the struct field of request, the clients
+// only know the fields by it.
+func jsonName(request interface{}, field string) string {
+	t := reflect.TypeOf(request)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	f, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field
+}
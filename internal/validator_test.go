@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"article-app/internal/domain"
+	"strings"
+	"testing"
+
+	"github.com/beego/i18n"
+)
+
+type validatorTestRequest struct {
+	Title string `json:"title" valid:"Required"`
+	Code  string `json:"code" valid:"Alpha"`
+}
+
+func setTestMessages(t *testing.T) {
+	if i18n.IsExist("en-US") {
+		return
+	}
+	if err := i18n.SetMessage("en-US", "../conf/en.ini"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateTranslatesRules(t *testing.T) {
+	setTestMessages(t)
+
+	errs, err := Validate("en-US", validatorTestRequest{Code: "a1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("errors = %+v, want 2", errs)
+	}
+
+	if errs[0].Field != "title" || errs[0].Description != "must not be empty." {
+		t.Errorf("translated rule = %+v", errs[0])
+	}
+	// Alpha has no translation, the message of beego is kept
+	if errs[1].Field != "code" || errs[1].Description != "Must be valid alpha characters" {
+		t.Errorf("untranslated rule = %+v", errs[1])
+	}
+}
+
+func TestValidateArticleBodyBytes(t *testing.T) {
+	setTestMessages(t)
+
+	// 3 bytes a character, within MaxSize(65535) but not within the text column
+	body := strings.Repeat("語", domain.MaxArticleBodyBytes/3+1)
+	for _, request := range []interface{}{
+		domain.CreateArticleStoreRequest{Title: "title", Body: body},
+		domain.UpdateArticleRequest{Body: body},
+	} {
+		errs, err := Validate("en-US", request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != 1 || errs[0].Field != "body" || errs[0].Description != "must be at most 65535 bytes long." {
+			t.Errorf("%T: errors = %+v", request, errs)
+		}
+	}
+
+	body = strings.Repeat("語", domain.MaxArticleBodyBytes/3)
+	if errs, err := Validate("en-US", domain.CreateArticleStoreRequest{Title: "title", Body: body}); err != nil || len(errs) != 0 {
+		t.Errorf("body of %d bytes: errors = %+v, %v", len(body), errs, err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"article-app/internal/middlewares"
 	"strings"
 
+	roleRepo "article-app/internal/data/role/repository"
+
 	userHandler "article-app/internal/data/user/delivery/http"
 	userRepo "article-app/internal/data/user/repository"
 	userUsecase "article-app/internal/data/user/usecase"
@@ -12,10 +14,33 @@ import (
 	articleHandler "article-app/internal/data/article/delivery/http"
 	articleRepo "article-app/internal/data/article/repository"
 	articleUsecase "article-app/internal/data/article/usecase"
+
+	tagHandler "article-app/internal/data/tag/delivery/http"
+	tagRepo "article-app/internal/data/tag/repository"
+	tagUsecase "article-app/internal/data/tag/usecase"
+
+	categoryHandler "article-app/internal/data/category/delivery/http"
+	categoryRepo "article-app/internal/data/category/repository"
+	categoryUsecase "article-app/internal/data/category/usecase"
+
+	commentHandler "article-app/internal/data/comment/delivery/http"
+	commentRepo "article-app/internal/data/comment/repository"
+	commentUsecase "article-app/internal/data/comment/usecase"
+
+	errorCodeHandler "article-app/internal/data/errorcode/delivery/http"
+	searchHandler "article-app/internal/data/search/delivery/http"
+	searchRepo "article-app/internal/data/search/repository"
+	searchUsecase "article-app/internal/data/search/usecase"
+
 	"article-app/internal/domain"
 	"article-app/pkg/database"
 	"article-app/pkg/jwt"
+	"article-app/pkg/mailer"
+	"article-app/pkg/migration"
+	"article-app/pkg/scheduler"
+	"article-app/pkg/search"
 	"article-app/pkg/seeder"
+	"context"
 	"errors"
 	"log"
 	"net/http"
@@ -30,8 +55,17 @@ import (
 
 func main() {
 
-	// token expired
-	tokenExpired := beego.AppConfig.DefaultInt64("tokenExpired", 86400)
+	// token expired, the access token is short lived and renewed with the refresh token
+	tokenExpired := beego.AppConfig.DefaultInt64("tokenExpired", 900)
+	// store of the jwt identification marks: none, memory, database or redis; none
+	// keeps the tokens valid on every instance and across restarts
+	jwtAdapter := beego.AppConfig.DefaultString("jwtAdapter", "none")
+	// refresh token expired
+	refreshTokenExpired := beego.AppConfig.DefaultInt("refreshTokenExpired", 2592000)
+	// email verification token expired
+	verifyTokenExpired := beego.AppConfig.DefaultInt("verifyTokenExpired", 86400)
+	// password reset token expired
+	resetTokenExpired := beego.AppConfig.DefaultInt("resetTokenExpired", 3600)
 	// global execution timeout
 	serverTimeout := beego.AppConfig.DefaultInt64("serverTimeout", 60)
 	// global execution timeout
@@ -40,6 +74,20 @@ func main() {
 	timeoutContext := time.Duration(requestTimeout) * time.Second
 	// jwt secret key
 	jwtSecretKey := beego.AppConfig.DefaultString("jwtSecretKey", "secret")
+	// jwt issuer, the identification marks are keyed by it so it does not depend on the host of a request
+	jwtIssuer := beego.AppConfig.DefaultString("jwtIssuer", beego.BConfig.AppName)
+	// reject article updates and deletes without If-Match
+	requireIfMatch := beego.AppConfig.DefaultBool("requireIfMatch", false)
+	// scheduled publishing interval, 0 disables it
+	schedulerInterval := beego.AppConfig.DefaultInt("schedulerInterval", 60)
+	// search index snapshot, rebuilt when missing or stale
+	searchIndexPath := beego.AppConfig.DefaultString("searchIndexPath", "data/search.idx")
+	// search index save interval, 0 saves it on shutdown only
+	searchFlushInterval := beego.AppConfig.DefaultInt("searchFlushInterval", 30)
+	// search index check against the articles interval, catches the writes of the other instances, 0 disables it
+	searchSyncInterval := beego.AppConfig.DefaultInt("searchSyncInterval", 60)
+	// full text search of the article list: mysql or local
+	searchDriver := beego.AppConfig.DefaultString("searchDriver", "mysql")
 	// log path
 
 	// languange
@@ -50,6 +98,10 @@ func main() {
 			panic("Failed to set message file for l10n")
 		}
 	}
+	// every error code needs its message in every language, the tests enforce it
+	for _, err := range domain.CheckErrorCodes(languages...) {
+		log.Println("warning:", err)
+	}
 
 	// beego config
 	beego.BConfig.Log.AccessLogs = false
@@ -62,9 +114,22 @@ func main() {
 	if beego.BConfig.RunMode != "prod" {
 		// db auto migrate dev environment
 		err := db.AutoMigrate(
+			&domain.Permission{},
+			&domain.Role{},
 			&domain.User{},
+			&domain.Tag{},
+			&domain.Category{},
 			&domain.Article{},
+			&domain.ArticleRevision{},
+			&domain.ArticleSlug{},
+			&domain.Comment{},
+			&domain.RefreshToken{},
+			&domain.UserToken{},
+			&jwt.IdentityMark{},
 		)
+		if err == nil {
+			err = migration.Migrate(db)
+		}
 		if err == nil && db.Migrator().HasTable(&domain.User{}) {
 			if err := db.First(&domain.User{}).Error; errors.Is(err, gorm.ErrRecordNotFound) {
 				//Insert seed data
@@ -103,11 +168,83 @@ func main() {
 		panic(err)
 	}
 
+	// identification marks of the tokens, without them tokens cannot be revoked
+	switch jwtAdapter {
+	case "memory":
+		auth.SetAdapter(jwt.NewMemoryAdapter())
+	case "database":
+		identityAdapter := jwt.NewDatabaseAdapter(db)
+		auth.SetAdapter(identityAdapter)
+
+		identityPurger := scheduler.New(time.Hour, func(ctx context.Context) {
+			if err := identityAdapter.Purge(ctx); err != nil {
+				log.Println("error purging jwt identities:", err)
+			}
+		})
+		identityPurger.Start()
+		beego.BeeApp.Server.RegisterOnShutdown(identityPurger.Stop)
+	case "redis":
+		identityAdapter, err := jwt.NewRedisAdapter(&jwt.Config{
+			Address:       beego.AppConfig.DefaultString("redisAddress", "127.0.0.1:6379"),
+			Db:            beego.AppConfig.DefaultInt("redisDb", 0),
+			Pass:          beego.AppConfig.DefaultString("redisPass", ""),
+			MaxIdle:       beego.AppConfig.DefaultInt("redisMaxIdle", 10),
+			MaxActive:     beego.AppConfig.DefaultInt("redisMaxActive", 0),
+			WaitTimeout:   timeoutContext,
+			ReadTimeout:   time.Second,
+			WriteTimeout:  time.Second,
+			MasterName:    beego.AppConfig.DefaultString("redisMasterName", ""),
+			TLS:           beego.AppConfig.DefaultBool("redisTls", false),
+			TLSSkipVerify: beego.AppConfig.DefaultBool("redisTlsSkipVerify", false),
+		})
+		if err != nil {
+			panic(err)
+		}
+		auth.SetAdapter(identityAdapter)
+		beego.BeeApp.Server.RegisterOnShutdown(func() {
+			identityAdapter.Close()
+		})
+	case "none":
+	default:
+		panic("unknown jwtAdapter " + jwtAdapter)
+	}
+
+	// mails of the app, the file mailer keeps them in a file for local runs. The
+	// mails hold the verification and reset tokens, so prod only sends them.
+	var appMailer mailer.Mailer
+	mailFrom := beego.AppConfig.DefaultString("mailFrom", "no-reply@localhost")
+	defaultMailer := "log"
+	if beego.BConfig.RunMode == "prod" {
+		defaultMailer = "smtp"
+	}
+	mailerDriver := beego.AppConfig.DefaultString("mailer", defaultMailer)
+	if beego.BConfig.RunMode == "prod" && mailerDriver != "smtp" {
+		panic("mailer " + mailerDriver + " would expose the mailed tokens, use smtp in prod")
+	}
+	switch mailerDriver {
+	case "log":
+		appMailer = mailer.NewFileMailer("", mailFrom)
+	case "file":
+		appMailer = mailer.NewFileMailer(beego.AppConfig.DefaultString("mailFile", "data/mail.log"), mailFrom)
+	case "smtp":
+		appMailer = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:          beego.AppConfig.DefaultString("smtpHost", "127.0.0.1"),
+			Port:          beego.AppConfig.DefaultInt("smtpPort", 587),
+			Username:      beego.AppConfig.DefaultString("smtpUsername", ""),
+			Password:      beego.AppConfig.DefaultString("smtpPassword", ""),
+			From:          mailFrom,
+			TLS:           beego.AppConfig.DefaultBool("smtpTls", false),
+			TLSSkipVerify: beego.AppConfig.DefaultBool("smtpTlsSkipVerify", false),
+		})
+	default:
+		panic("unknown mailer " + mailerDriver)
+	}
+
 	// middleware init
 	beego.InsertFilter("*", beego.BeforeRouter, cors.Allow(&cors.Options{
 		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowHeaders:     []string{"Origin", "Authorization", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type", "ETag", "Link", "X-Total-Count"},
 		AllowCredentials: true,
 		AllowAllOrigins:  true,
 	}))
@@ -117,17 +254,93 @@ func main() {
 	// default error handler
 	beego.ErrorController(&internal.BaseController{})
 
+	// search index embedded in the process, stemming the languages of the app
+	searchIndex := search.NewIndex(search.Analyzer{Languages: search.LookupLanguages(languages...)})
+	searchRepository := searchRepo.NewSearchRepository(db, searchIndex, searchIndexPath)
+	if err := searchIndex.Load(searchIndexPath); err != nil {
+		log.Println("rebuilding search index:", err)
+		if err := searchRepository.Rebuild(context.Background()); err != nil {
+			panic(err)
+		}
+	} else if err := searchRepository.Sync(context.Background()); err != nil {
+		panic(err)
+	}
+
+	// full text search of the article list, the FULLTEXT index of MySQL or the embedded index
+	var articleSearcher domain.ArticleSearcher
+	switch searchDriver {
+	case "mysql":
+		articleSearcher = articleRepo.NewMySQLArticleSearcher()
+	case "local":
+		articleSearcher = articleRepo.NewLocalArticleSearcher(searchIndex)
+	default:
+		panic("unknown searchDriver " + searchDriver)
+	}
+
 	// init repository
 	userRepository := userRepo.NewUserRepository(db)
-	articleRepository := articleRepo.NewArticleRepository(db)
+	refreshTokenRepository := userRepo.NewRefreshTokenRepository(db)
+	userTokenRepository := userRepo.NewUserTokenRepository(db)
+	roleRepository := roleRepo.NewRoleRepository(db)
+	articleRepository := articleRepo.NewArticleRepository(db, articleSearcher)
+	articleRevisionRepository := articleRepo.NewArticleRevisionRepository(db)
+	tagRepository := tagRepo.NewTagRepository(db)
+	categoryRepository := categoryRepo.NewCategoryRepository(db)
+	commentRepository := commentRepo.NewCommentRepository(db)
 
 	// init usecase
-	userUsecase := userUsecase.NewUserUseCase(timeoutContext, userRepository, auth, int(tokenExpired))
-	articleUsecase := articleUsecase.NewArticleUseCase(timeoutContext, articleRepository, auth, int(tokenExpired))
+	userUsecase := userUsecase.NewUserUseCase(timeoutContext, userRepository, roleRepository, refreshTokenRepository, userTokenRepository, auth, jwtIssuer, appMailer, int(tokenExpired), refreshTokenExpired, verifyTokenExpired, resetTokenExpired)
+	articleUsecase := articleUsecase.NewArticleUseCase(timeoutContext, articleRepository, articleRevisionRepository, tagRepository, categoryRepository, searchRepository, auth, int(tokenExpired))
+	tagUsecase := tagUsecase.NewTagUseCase(timeoutContext, tagRepository)
+	categoryUsecase := categoryUsecase.NewCategoryUseCase(timeoutContext, categoryRepository)
+	commentUsecase := commentUsecase.NewCommentUseCase(timeoutContext, commentRepository, articleRepository, auth)
+	searchUsecase := searchUsecase.NewSearchUseCase(timeoutContext, searchRepository)
+
+	// role based access control, route permissions are declared by each handler
+	rbac := middlewares.NewRbacMiddleware(auth, roleRepository)
+
+	// scheduled publishing, due rows are claimed with SKIP LOCKED so every instance can run it
+	publishScheduler := scheduler.New(time.Duration(schedulerInterval)*time.Second, func(ctx context.Context) {
+		if published, err := articleUsecase.PublishScheduledArticles(ctx, time.Now()); err != nil {
+			log.Println("error publishing scheduled articles:", err)
+		} else if published > 0 {
+			log.Println("published scheduled articles:", published)
+		}
+	})
+	publishScheduler.Start()
+	beego.BeeApp.Server.RegisterOnShutdown(publishScheduler.Stop)
+
+	// the search index is saved when it changed, and once more on shutdown
+	flushSearchIndex := func(ctx context.Context) {
+		if err := searchRepository.Flush(); err != nil {
+			log.Println("error saving search index:", err)
+		}
+	}
+	searchFlusher := scheduler.New(time.Duration(searchFlushInterval)*time.Second, flushSearchIndex)
+	searchFlusher.Start()
+	beego.BeeApp.Server.RegisterOnShutdown(func() {
+		searchFlusher.Stop()
+		flushSearchIndex(context.Background())
+	})
+
+	// the search index is compared with the articles, so the writes of the
+	// other instances and the ones lost before a flush are indexed
+	searchSyncer := scheduler.New(time.Duration(searchSyncInterval)*time.Second, func(ctx context.Context) {
+		if err := searchRepository.Sync(ctx); err != nil {
+			log.Println("error syncing search index:", err)
+		}
+	})
+	searchSyncer.Start()
+	beego.BeeApp.Server.RegisterOnShutdown(searchSyncer.Stop)
 
 	// init handler
-	userHandler.NewUserHandler(userUsecase, auth)
-	articleHandler.NewArticleHandler(articleUsecase, auth)
+	userHandler.NewUserHandler(userUsecase, auth, rbac)
+	articleHandler.NewArticleHandler(articleUsecase, auth, rbac, requireIfMatch)
+	tagHandler.NewTagHandler(tagUsecase, rbac)
+	categoryHandler.NewCategoryHandler(categoryUsecase, rbac)
+	commentHandler.NewCommentHandler(commentUsecase, rbac)
+	searchHandler.NewSearchHandler(searchUsecase, rbac)
+	errorCodeHandler.NewErrorCodeHandler()
 
 	// Initializing the server in a goroutine so that
 	// it won't block the graceful shutdown handling below
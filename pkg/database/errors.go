@@ -0,0 +1,26 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	mysqlDuplicateEntry    = 1062
+	sqliteConstraintUnique = 2067
+)
+
+// IsDuplicateKey reports whether err is the violation of a unique index, as
+// reported by MySQL or by the SQLite driver of the tests.
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+	var codeErr interface{ Code() int }
+	if errors.As(err, &codeErr) {
+		return codeErr.Code() == sqliteConstraintUnique
+	}
+	return false
+}
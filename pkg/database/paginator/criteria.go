@@ -0,0 +1,154 @@
+package paginator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Operator compares a field with a value inside a Condition.
+type Operator string
+
+const (
+	OpEq        Operator = "="
+	OpNotEq     Operator = "<>"
+	OpGt        Operator = ">"
+	OpGte       Operator = ">="
+	OpLt        Operator = "<"
+	OpLte       Operator = "<="
+	OpLike      Operator = "LIKE"
+	OpIn        Operator = "IN"
+	OpNotIn     Operator = "NOT IN"
+	OpIsNull    Operator = "IS NULL"
+	OpIsNotNull Operator = "IS NOT NULL"
+)
+
+// likeEscape is the escape character of LIKE patterns. It is not a backslash
+// because MySQL and SQLite disagree on how a backslash literal is written.
+const likeEscape = "!"
+
+var (
+	ErrInvalidField    = errors.New("criteria field is invalid")
+	ErrInvalidOperator = errors.New("criteria operator is invalid")
+
+	fieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+	likeReplacer = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+)
+
+// Criteria is a WHERE condition whose values are always bound as placeholders,
+// so user input never becomes part of the SQL text.
+//
+//	criteria := paginator.And(
+//		paginator.Eq("articles.status", status),
+//		paginator.Or(
+//			paginator.Contains("articles.title", search),
+//			paginator.Contains("articles.body", search),
+//		),
+//	)
+//	query, args, err := criteria.Build() // (articles.status = ? AND (articles.title LIKE ? ESCAPE '!' OR ...))
+type Criteria interface {
+	// Build returns the SQL fragment with ? placeholders and the values bound to them.
+	// An empty fragment means the criteria matches everything.
+	Build() (string, []interface{}, error)
+}
+
+// Condition compares a single field. Field is an identifier such as
+// "articles.title", it is validated but never quoted from user input.
+type Condition struct {
+	Field    string
+	Operator Operator
+	Value    interface{}
+}
+
+func Where(field string, operator Operator, value interface{}) Condition {
+	return Condition{Field: field, Operator: operator, Value: value}
+}
+
+func Eq(field string, value interface{}) Condition {
+	return Where(field, OpEq, value)
+}
+
+func In(field string, values interface{}) Condition {
+	return Where(field, OpIn, values)
+}
+
+func IsNull(field string) Condition {
+	return Where(field, OpIsNull, nil)
+}
+
+func IsNotNull(field string) Condition {
+	return Where(field, OpIsNotNull, nil)
+}
+
+// Contains matches field against term anywhere in the value, LIKE wildcards in
+// term are escaped and match literally.
+func Contains(field, term string) Condition {
+	return Where(field, OpLike, "%"+EscapeLike(term)+"%")
+}
+
+// EscapeLike escapes the LIKE wildcards in s for a pattern built by OpLike.
+func EscapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+func (c Condition) Build() (string, []interface{}, error) {
+	if !fieldPattern.MatchString(c.Field) {
+		return "", nil, fmt.Errorf("%w: %q", ErrInvalidField, c.Field)
+	}
+
+	switch c.Operator {
+	case OpEq, OpNotEq, OpGt, OpGte, OpLt, OpLte:
+		return c.Field + " " + string(c.Operator) + " ?", []interface{}{c.Value}, nil
+	case OpLike:
+		return c.Field + " LIKE ? ESCAPE '" + likeEscape + "'", []interface{}{c.Value}, nil
+	case OpIn, OpNotIn:
+		return c.Field + " " + string(c.Operator) + " ?", []interface{}{c.Value}, nil
+	case OpIsNull, OpIsNotNull:
+		return c.Field + " " + string(c.Operator), nil, nil
+	default:
+		return "", nil, fmt.Errorf("%w: %q", ErrInvalidOperator, c.Operator)
+	}
+}
+
+// Group joins criteria with the same conjunction, nil and empty members are skipped.
+type Group struct {
+	Conjunction string
+	Items       []Criteria
+}
+
+func And(items ...Criteria) Group {
+	return Group{Conjunction: "AND", Items: items}
+}
+
+func Or(items ...Criteria) Group {
+	return Group{Conjunction: "OR", Items: items}
+}
+
+func (g Group) Build() (string, []interface{}, error) {
+	var parts []string
+	var args []interface{}
+	for _, item := range g.Items {
+		if item == nil {
+			continue
+		}
+		query, itemArgs, err := item.Build()
+		if err != nil {
+			return "", nil, err
+		}
+		if query == "" {
+			continue
+		}
+		parts = append(parts, query)
+		args = append(args, itemArgs...)
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil, nil
+	case 1:
+		return parts[0], args, nil
+	default:
+		return "(" + strings.Join(parts, " "+g.Conjunction+" ") + ")", args, nil
+	}
+}
@@ -0,0 +1,140 @@
+package paginator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+const hostile = `' OR 1=1 --`
+
+type testArticle struct {
+	ID     int
+	Title  string
+	Status string
+}
+
+// dryRunDB renders the statements without a database, so the SQL text and the
+// bound values can be inspected.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestCriteriaBindsHostileValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria Criteria
+		want     interface{}
+	}{
+		{"eq", Eq("test_articles.status", hostile), hostile},
+		{"not eq", Where("test_articles.status", OpNotEq, hostile), hostile},
+		{"contains", Contains("test_articles.title", hostile), "%" + hostile + "%"},
+		{"group", Or(Eq("test_articles.id", 1), Eq("test_articles.title", hostile)), hostile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := filter(dryRunDB(t), nil, tt.criteria)
+			if err != nil {
+				t.Fatalf("filter() error = %v", err)
+			}
+			stmt := db.Find(&[]testArticle{}).Statement
+			sql := stmt.SQL.String()
+
+			if strings.Contains(sql, "1=1") || strings.Contains(sql, "--") {
+				t.Errorf("hostile value in the SQL text: %s", sql)
+			}
+			found := false
+			for _, v := range stmt.Vars {
+				if v == tt.want {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("vars = %v, want %q bound", stmt.Vars, tt.want)
+			}
+		})
+	}
+}
+
+func TestCriteriaBindsHostileList(t *testing.T) {
+	db, err := filter(dryRunDB(t), nil, In("test_articles.status", []string{"draft", hostile}))
+	if err != nil {
+		t.Fatalf("filter() error = %v", err)
+	}
+	stmt := db.Find(&[]testArticle{}).Statement
+	if sql := stmt.SQL.String(); strings.Contains(sql, "1=1") {
+		t.Errorf("hostile value in the SQL text: %s", sql)
+	}
+	if len(stmt.Vars) != 2 || stmt.Vars[1] != hostile {
+		t.Errorf("vars = %v, want both values bound", stmt.Vars)
+	}
+}
+
+func TestCriteriaEscapesLikeWildcards(t *testing.T) {
+	_, args, err := Contains("test_articles.title", "100%_!").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "%100!%!_!!%"; args[0] != want {
+		t.Errorf("pattern = %q, want %q", args[0], want)
+	}
+}
+
+func TestCriteriaRejectsHostileIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria Criteria
+		want     error
+	}{
+		{"field", Eq("title"+hostile, 1), ErrInvalidField},
+		{"field statement", Eq("title; DROP TABLE users", 1), ErrInvalidField},
+		{"field nested in group", And(Eq("test_articles.id", 1), Eq("id) OR (1=1", 1)), ErrInvalidField},
+		{"operator", Where("test_articles.id", Operator("= 1 OR 1=1 --"), 1), ErrInvalidOperator},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := filter(dryRunDB(t), nil, tt.criteria); !errors.Is(err, tt.want) {
+				t.Errorf("filter() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortSpecRejectsUnknownFields(t *testing.T) {
+	spec := SortSpec{
+		Fields:     map[string]string{"id": "test_articles.id", "title": "test_articles.title"},
+		Default:    "-id",
+		TieBreaker: "id",
+	}
+
+	for _, input := range []string{hostile, "title desc", "-title;DROP TABLE users", "id,password"} {
+		_, err := spec.Parse(input)
+		var sortErr *SortError
+		if !errors.As(err, &sortErr) || !errors.Is(err, ErrSortInvalid) {
+			t.Errorf("Parse(%q) error = %v, want a *SortError", input, err)
+			continue
+		}
+		if strings.Join(sortErr.Allowed, ",") != "id,title" {
+			t.Errorf("Parse(%q) allowed = %v", input, sortErr.Allowed)
+		}
+	}
+
+	sort, err := spec.Parse("-title")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := sort.OrderBy(), "test_articles.title desc, test_articles.id asc"; got != want {
+		t.Errorf("OrderBy() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,245 @@
+package paginator
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var ErrCursorInvalid = errors.New("cursor is invalid")
+
+// CursorPage is one page of records fetched by a CursorPaginator.
+// Can be sent to the client directly.
+type CursorPage[T any] struct {
+	PageSize   int    `json:"page_size"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+	Records    []T    `json:"records"`
+}
+
+// MapCursorPage converts every record of page with fn and keeps the cursors.
+func MapCursorPage[T, R any](page *CursorPage[T], fn func(T) R) *CursorPage[R] {
+	if page == nil {
+		return nil
+	}
+
+	records := make([]R, len(page.Records))
+	for k, v := range page.Records {
+		records[k] = fn(v)
+	}
+	return &CursorPage[R]{
+		PageSize:   page.PageSize,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Records:    records,
+	}
+}
+
+// CursorPaginator pages through records with keyset pagination. Instead of an
+// offset the opaque cursors carry the sort key of the first and last record,
+// so pages stay fast and stable while rows are inserted.
+type CursorPaginator[T any] struct {
+	db *gorm.DB
+
+	pageSize int
+}
+
+// cursorToken is the decoded form of a cursor. Sort pins the cursor to the
+// sort it was issued for, Values holds the sort key of the boundary record.
+type cursorToken struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	Prev   bool              `json:"p,omitempty"`
+}
+
+// NewCursorPaginator create a new CursorPaginator, T must be a gorm model.
+func NewCursorPaginator[T any](db *gorm.DB, pageSize int) *CursorPaginator[T] {
+	return &CursorPaginator[T]{
+		db:       db,
+		pageSize: pageSize,
+	}
+}
+
+// FindWithFilter fetches the page next to cursor, an empty cursor starts at the
+// first page. The columns in sort must be NOT NULL and should end with a unique
+// one, the total is only counted when withTotal is set.
+func (p *CursorPaginator[T]) FindWithFilter(ctx context.Context, cursor string, sort Sort, withTotal bool, fields, associate []string, criteria Criteria) (*CursorPage[T], error) {
+	if len(sort) == 0 {
+		return nil, ErrSortInvalid
+	}
+
+	stmt := &gorm.Statement{DB: p.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+
+	var token *cursorToken
+	var seek Criteria
+	if cursor != "" {
+		var err error
+		if token, err = decodeCursor(cursor, sort); err == nil {
+			seek, err = seekCriteria(stmt.Schema, sort, token)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := filter(p.db.WithContext(ctx), associate, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CursorPage[T]{PageSize: p.pageSize}
+	if withTotal {
+		total := int64(0)
+		if err = db.Model(new(T)).Count(&total).Error; err != nil {
+			return nil, err
+		}
+		res.Total = &total
+	}
+
+	backward := token != nil && token.Prev
+	order := sort
+	if seek != nil {
+		query, args, err := seek.Build()
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(query, args...)
+	}
+	if backward {
+		order = sort.reverse()
+	}
+	if len(fields) > 0 {
+		db = db.Select(strings.Join(fields, ","))
+	}
+
+	// one extra record tells whether there is another page in the same direction
+	rows := []T{}
+	if err = db.Limit(p.pageSize + 1).Order(order.OrderBy()).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	more := len(rows) > p.pageSize
+	if more {
+		rows = rows[:p.pageSize]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	res.Records = rows
+	if len(rows) == 0 {
+		return res, nil
+	}
+
+	if more || backward {
+		if res.NextCursor, err = encodeCursor(ctx, stmt.Schema, sort, reflect.ValueOf(&rows[len(rows)-1]), false); err != nil {
+			return nil, err
+		}
+	}
+	if (backward && more) || (!backward && token != nil) {
+		if res.PrevCursor, err = encodeCursor(ctx, stmt.Schema, sort, reflect.ValueOf(&rows[0]), true); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// seekCriteria selects the records after the cursor in the sort order, or
+// before it for a prev cursor:
+//
+//	a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND c > ?)
+func seekCriteria(s *schema.Schema, sort Sort, token *cursorToken) (Criteria, error) {
+	values := make([]interface{}, len(sort))
+	for k, v := range sort {
+		field := lookUpSortField(s, v)
+		if field == nil {
+			return nil, ErrCursorInvalid
+		}
+
+		ptr := reflect.New(field.FieldType)
+		if err := json.Unmarshal(token.Values[k], ptr.Interface()); err != nil {
+			return nil, ErrCursorInvalid
+		}
+		if ptr.Elem().Kind() == reflect.Ptr && ptr.Elem().IsNil() {
+			return nil, ErrCursorInvalid
+		}
+		values[k] = ptr.Elem().Interface()
+	}
+
+	var branches []Criteria
+	for k, v := range sort {
+		operator := OpGt
+		if v.Desc != token.Prev {
+			operator = OpLt
+		}
+
+		var branch []Criteria
+		for i := 0; i < k; i++ {
+			branch = append(branch, Eq(sort[i].Column, values[i]))
+		}
+		branch = append(branch, Where(v.Column, operator, values[k]))
+		branches = append(branches, And(branch...))
+	}
+	return Or(branches...), nil
+}
+
+func encodeCursor(ctx context.Context, s *schema.Schema, sort Sort, row reflect.Value, prev bool) (string, error) {
+	row = reflect.Indirect(row)
+	token := cursorToken{Sort: sort.String(), Prev: prev}
+	for _, v := range sort {
+		field := lookUpSortField(s, v)
+		if field == nil {
+			return "", ErrCursorInvalid
+		}
+
+		value, _ := field.ValueOf(ctx, row)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		token.Values = append(token.Values, raw)
+	}
+
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(cursor string, sort Sort) (*cursorToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+
+	var token cursorToken
+	if err = json.Unmarshal(raw, &token); err != nil {
+		return nil, ErrCursorInvalid
+	}
+	// a cursor only makes sense for the sort it was issued for
+	if token.Sort != sort.String() || len(token.Values) != len(sort) {
+		return nil, ErrCursorInvalid
+	}
+	return &token, nil
+}
+
+func lookUpSortField(s *schema.Schema, field SortField) *schema.Field {
+	column := field.Column
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		column = column[i+1:]
+	}
+	return s.LookUpField(column)
+}
@@ -0,0 +1,165 @@
+package paginator
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type cursorRow struct {
+	ID    int
+	Score int
+}
+
+// openCursorDB stores 7 rows with tied scores, in the order of cursorSort
+// they are 1 to 7.
+func openCursorDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&cursorRow{}); err != nil {
+		t.Fatal(err)
+	}
+	for id, score := range []int{3, 3, 2, 2, 2, 1, 1} {
+		if err = db.Create(&cursorRow{ID: id + 1, Score: score}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func cursorSort(t *testing.T) Sort {
+	t.Helper()
+	spec := SortSpec{
+		Fields:     map[string]string{"id": "cursor_rows.id", "score": "cursor_rows.score"},
+		Default:    "-score",
+		TieBreaker: "id",
+	}
+	sort, err := spec.Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sort
+}
+
+func ids(page *CursorPage[cursorRow]) []int {
+	res := []int{}
+	for _, v := range page.Records {
+		res = append(res, v.ID)
+	}
+	return res
+}
+
+func TestCursorPaginatorPagesForwardAndBack(t *testing.T) {
+	ctx := context.Background()
+	p := NewCursorPaginator[cursorRow](openCursorDB(t), 3)
+	sort := cursorSort(t)
+
+	type want struct {
+		ids        []int
+		next, prev bool
+	}
+	check := func(name string, page *CursorPage[cursorRow], w want) {
+		t.Helper()
+		if got := ids(page); !reflect.DeepEqual(got, w.ids) {
+			t.Errorf("%s: ids = %v, want %v", name, got, w.ids)
+		}
+		if (page.NextCursor != "") != w.next || (page.PrevCursor != "") != w.prev {
+			t.Errorf("%s: next = %q, prev = %q, want next %v, prev %v", name, page.NextCursor, page.PrevCursor, w.next, w.prev)
+		}
+	}
+	find := func(cursor string) *CursorPage[cursorRow] {
+		t.Helper()
+		page, err := p.FindWithFilter(ctx, cursor, sort, true, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("FindWithFilter(%q) error = %v", cursor, err)
+		}
+		return page
+	}
+
+	first := find("")
+	check("first", first, want{[]int{1, 2, 3}, true, false})
+	if first.Total == nil || *first.Total != 7 {
+		t.Errorf("total = %v, want 7", first.Total)
+	}
+	// the next page starts inside the run of score 2
+	second := find(first.NextCursor)
+	check("second", second, want{[]int{4, 5, 6}, true, true})
+	last := find(second.NextCursor)
+	check("last", last, want{[]int{7}, false, true})
+
+	back := find(last.PrevCursor)
+	check("back to second", back, want{[]int{4, 5, 6}, true, true})
+	backFirst := find(back.PrevCursor)
+	check("back to first", backFirst, want{[]int{1, 2, 3}, true, false})
+	check("forward again", find(backFirst.NextCursor), want{[]int{4, 5, 6}, true, true})
+}
+
+func TestCursorPaginatorBackFromPartialPage(t *testing.T) {
+	ctx := context.Background()
+	p := NewCursorPaginator[cursorRow](openCursorDB(t), 2)
+	sort := cursorSort(t)
+
+	page, err := p.FindWithFilter(ctx, "", sort, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err = p.FindWithFilter(ctx, page.NextCursor, sort, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the records before 3 are only 1 and 2, there is no page before them
+	page, err = p.FindWithFilter(ctx, page.PrevCursor, sort, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(page); !reflect.DeepEqual(got, []int{1, 2}) || page.PrevCursor != "" || page.NextCursor == "" {
+		t.Errorf("ids = %v, next = %q, prev = %q", got, page.NextCursor, page.PrevCursor)
+	}
+}
+
+func TestCursorPaginatorRejectsInvalidCursors(t *testing.T) {
+	ctx := context.Background()
+	p := NewCursorPaginator[cursorRow](openCursorDB(t), 3)
+	sort := cursorSort(t)
+
+	first, err := p.FindWithFilter(ctx, "", sort, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := SortSpec{Fields: map[string]string{"id": "cursor_rows.id"}, TieBreaker: "id"}.Parse("-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   Sort
+	}{
+		{"not base64", "%%%", sort},
+		{"not json", encode("score"), sort},
+		{"other sort", first.NextCursor, other},
+		{"missing value", encode(`{"s":"-score,id","v":[2]}`), sort},
+		{"wrong type", encode(`{"s":"-score,id","v":["2","3"]}`), sort},
+		{"tampered", first.NextCursor[:len(first.NextCursor)-3] + "AAA", sort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.FindWithFilter(ctx, tt.cursor, tt.sort, false, nil, nil, nil); !errors.Is(err, ErrCursorInvalid) {
+				t.Errorf("FindWithFilter() error = %v, want %v", err, ErrCursorInvalid)
+			}
+		})
+	}
+}
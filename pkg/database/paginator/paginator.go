@@ -8,118 +8,107 @@ import (
 	"gorm.io/gorm"
 )
 
-// Paginator structure containing pagination information and result records.
+// Page is one page of records with its pagination information.
 // Can be sent to the client directly.
-type Paginator struct {
-	db *gorm.DB
-
-	MaxPage     int64
-	Total       int64
-	PageSize    int
-	CurrentPage int
-	Records     interface{}
+type Page[T any] struct {
+	MaxPage     int64 `json:"max_page"`
+	Total       int64 `json:"total"`
+	PageSize    int   `json:"page_size"`
+	CurrentPage int   `json:"current_page"`
+	Records     []T   `json:"records"`
 }
 
-func paginateScope(ctx context.Context, page, pageSize int) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		offset := (page - 1) * pageSize
-		return db.WithContext(ctx).Offset(offset).Limit(pageSize)
-	}
-}
-
-// NewPaginator create a new Paginator.
-//
-// Given DB transaction can contain clauses already, such as WHERE, if you want to
-// filter results.
+// MapPage converts every record of page with fn and keeps the pagination
+// information, e.g. to turn a page of entities into a page of responses.
 //
-//	articles := []model.Article{}
-//	tx := database.Conn().Where("title LIKE ?", "%"+helper.EscapeLike(search)+"%")
-//	paginator := database.NewPaginator(tx, page, pageSize, &articles)
-//	result := paginator.Find()
-//	if response.HandleDatabaseError(result) {
-//	    response.JSON(http.StatusOK, paginator)
-//	}
-func NewPaginator(db *gorm.DB, page, pageSize int, dest interface{}) *Paginator {
-	return &Paginator{
-		db:          db,
-		CurrentPage: page,
-		PageSize:    pageSize,
-		Records:     dest,
+//	responses := paginator.MapPage(page, domain.Article.ToArticleResponse)
+func MapPage[T, R any](page *Page[T], fn func(T) R) *Page[R] {
+	if page == nil {
+		return nil
 	}
-}
-
-func (p *Paginator) updatePageInfo() error {
-	count := int64(0)
 
-	if err := p.db.Model(p.Records).Count(&count).Error; err != nil {
-		return err
+	records := make([]R, len(page.Records))
+	for k, v := range page.Records {
+		records[k] = fn(v)
 	}
-
-	p.MaxPage = int64(math.Ceil(float64(count) / float64(p.PageSize)))
-	if p.MaxPage == 0 {
-		p.MaxPage = 1
+	return &Page[R]{
+		MaxPage:     page.MaxPage,
+		Total:       page.Total,
+		PageSize:    page.PageSize,
+		CurrentPage: page.CurrentPage,
+		Records:     records,
 	}
-	return nil
 }
 
-func (p *Paginator) updatePageInfoWithFilter(associate []string) error {
-	db := p.db.Model(p.Records)
-	count := int64(0)
+// Paginator fetches pages of the records of type T.
+type Paginator[T any] struct {
+	db *gorm.DB
 
-	if len(associate) > 0 {
-		for _, v := range associate {
-			db.Joins(v)
-		}
-	}
-	if err := db.Count(&count).Error; err != nil {
-		return err
-	}
+	page     int
+	pageSize int
+}
 
-	p.Total = count
-	p.MaxPage = int64(math.Ceil(float64(count) / float64(p.PageSize)))
-	if p.MaxPage == 0 {
-		p.MaxPage = 1
+// NewPaginator create a new Paginator.
+//
+// Given DB transaction can contain clauses already, such as Preload, the
+// filters should be given as Criteria to FindWithFilter.
+//
+//	p := paginator.NewPaginator[domain.Article](db.Preload("Tags"), page, pageSize)
+//	res, err := p.FindWithFilter(ctx, "articles.id desc", nil, nil, paginator.Eq("articles.status", status))
+func NewPaginator[T any](db *gorm.DB, page, pageSize int) *Paginator[T] {
+	return &Paginator[T]{
+		db:       db,
+		page:     page,
+		pageSize: pageSize,
 	}
-	return nil
 }
 
-// Find requests page information (total records and max page) and
-// executes the transaction. Paginate struct is updated automatically, as
-// well as the destination slice given in NewPaginate().
-func (p *Paginator) Find(ctx context.Context) *gorm.DB {
-	p.updatePageInfo()
-	return p.db.Scopes(paginateScope(ctx, p.CurrentPage, p.PageSize)).Find(p.Records)
-}
+// FindWithFilter counts and fetches the current page of the records matching
+// criteria. The associations are joined in both queries so criteria may refer
+// to their columns, a nil criteria matches every record.
+func (p *Paginator[T]) FindWithFilter(ctx context.Context, order string, fields, associate []string, criteria Criteria) (*Page[T], error) {
+	db, err := filter(p.db.WithContext(ctx), associate, criteria)
+	if err != nil {
+		return nil, err
+	}
 
-func (p *Paginator) FindWithFilter(ctx context.Context, order string, fields, associate, criteria []string, args ...interface{}) *gorm.DB {
-	p.updatePageInfoWithFilter(associate)
+	res := &Page[T]{PageSize: p.pageSize, CurrentPage: p.page}
+	if err = db.Model(new(T)).Count(&res.Total).Error; err != nil {
+		return nil, err
+	}
+	res.MaxPage = int64(math.Ceil(float64(res.Total) / float64(p.pageSize)))
+	if res.MaxPage == 0 {
+		res.MaxPage = 1
+	}
 
-	//db := p.db.Scopes(paginateScope(ctx, p.CurrentPage, p.PageSize))
-	db := p.db.Limit(p.PageSize).Offset(p.PageSize * (p.CurrentPage - 1))
+	db = db.Limit(p.pageSize).Offset(p.pageSize * (p.page - 1))
 	if len(fields) > 0 {
 		db = db.Select(strings.Join(fields, ","))
 	}
-	if len(associate) > 0 {
-		for _, v := range associate {
-			db.Joins(v)
-		}
+	res.Records = []T{}
+	if err = db.Order(order).Find(&res.Records).Error; err != nil {
+		return nil, err
 	}
-	if len(criteria) > 0 && len(args) == len(criteria) {
-		for i := range criteria {
-			db = db.Where(criteria[i])
-		}
+	return res, nil
+}
+
+// filter joins the associations and applies criteria to db. The returned
+// session keeps the filtered statement reusable for both the count and the
+// find query.
+func filter(db *gorm.DB, associate []string, criteria Criteria) (*gorm.DB, error) {
+	for _, v := range associate {
+		db = db.Joins(v)
 	}
-	if len(criteria) > 0 {
-		for i := range criteria {
-			if strings.Contains(criteria[i], "OR") {
-				db = db.Or(criteria[i])
-			} else {
-				db = db.Where((criteria[i]))
-			}
+	if criteria != nil {
+		query, args, err := criteria.Build()
+		if err != nil {
+			return nil, err
+		}
+		if query != "" {
+			db = db.Where(query, args...)
 		}
-
 	}
-	return db.Order(order).Find(p.Records)
+	return db.Session(&gorm.Session{}), nil
 }
 
 func Pagination(pageRequest, pageSizeRequest int) (limit, page, offset int) {
@@ -0,0 +1,132 @@
+package paginator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrSortInvalid = errors.New("sort field is not allowed")
+
+// SortError reports a sort field outside the whitelist together with the
+// fields that are allowed, it can be sent to the client as is.
+type SortError struct {
+	Field   string   `json:"field"`
+	Allowed []string `json:"allowed"`
+}
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("%s: %q, allowed fields are %s", ErrSortInvalid, e.Field, strings.Join(e.Allowed, ", "))
+}
+
+func (e *SortError) Unwrap() error {
+	return ErrSortInvalid
+}
+
+// SortField is one entry of an ORDER BY clause.
+type SortField struct {
+	Name   string
+	Column string
+	Desc   bool
+}
+
+// Sort is a validated list of sort fields, only built by SortSpec.Parse.
+type Sort []SortField
+
+// OrderBy renders the ORDER BY clause, e.g. "articles.created_at desc, articles.id asc".
+func (s Sort) OrderBy() string {
+	parts := make([]string, len(s))
+	for k, v := range s {
+		direction := "asc"
+		if v.Desc {
+			direction = "desc"
+		}
+		parts[k] = v.Column + " " + direction
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String renders the sort back in the query param form, e.g. "-created_at,id".
+func (s Sort) String() string {
+	parts := make([]string, len(s))
+	for k, v := range s {
+		parts[k] = v.Name
+		if v.Desc {
+			parts[k] = "-" + v.Name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// reverse flips the direction of every field.
+func (s Sort) reverse() Sort {
+	res := make(Sort, len(s))
+	for k, v := range s {
+		v.Desc = !v.Desc
+		res[k] = v
+	}
+	return res
+}
+
+// SortSpec whitelists the fields a list can be sorted by.
+//
+//	spec := paginator.SortSpec{
+//		Fields:     map[string]string{"id": "articles.id", "title": "articles.title"},
+//		Default:    "-id",
+//		TieBreaker: "id",
+//	}
+//	sort, err := spec.Parse("-title") // articles.title desc, articles.id asc
+type SortSpec struct {
+	// Fields maps the names accepted from clients to their columns.
+	Fields map[string]string
+	// Default is the spec used when the client sends none.
+	Default string
+	// TieBreaker names a unique field appended when missing so pages are stable.
+	TieBreaker string
+}
+
+// Parse reads a comma separated list of field names, a leading "-" sorts the
+// field descending. Unknown fields return a *SortError.
+func (s SortSpec) Parse(spec string) (Sort, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		spec = s.Default
+	}
+
+	var res Sort
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+		if name == "" {
+			continue
+		}
+
+		column, ok := s.Fields[name]
+		if !ok {
+			return nil, &SortError{Field: name, Allowed: s.Allowed()}
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		res = append(res, SortField{Name: name, Column: column, Desc: desc})
+	}
+
+	if s.TieBreaker != "" && !seen[s.TieBreaker] {
+		res = append(res, SortField{Name: s.TieBreaker, Column: s.Fields[s.TieBreaker]})
+	}
+	return res, nil
+}
+
+// Allowed lists the accepted field names in alphabetical order.
+func (s SortSpec) Allowed() []string {
+	res := make([]string, 0, len(s.Fields))
+	for k := range s.Fields {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,136 @@
+package diff
+
+import "strings"
+
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// maxEdits bounds the work of a diff. Texts differing by more lines are
+// answered as the removal of the old lines followed by the new ones, which
+// keeps a diff of two large unrelated bodies cheap.
+const maxEdits = 1000
+
+// Line is a single line of a line-level diff.
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the line-level diff turning a into b, the shortest edit script
+// of both texts as long as it takes at most maxEdits lines.
+func Lines(a, b string) []Line {
+	from := splitLines(a)
+	to := splitLines(b)
+
+	// the common head and tail are kept as they are, the edits are searched in between
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(from)+len(to)-prefix-suffix)
+	lines = appendLines(lines, OpEqual, from[:prefix])
+	lines = append(lines, edits(from[prefix:len(from)-suffix], to[prefix:len(to)-suffix])...)
+	return appendLines(lines, OpEqual, from[len(from)-suffix:])
+}
+
+// edits finds the shortest edit script with the O(ND) algorithm of Myers, "An
+// O(ND) Difference Algorithm and Its Variations" (1986). A deleted line comes
+// before the line inserted in its place.
+func edits(from, to []string) []Line {
+	n, m := len(from), len(to)
+	max := n + m
+	if max > maxEdits {
+		max = maxEdits
+	}
+
+	// v[offset+k] is the furthest x reached on the diagonal k = x - y, trace
+	// keeps the diagonals -d..d of v after each d to walk the path back
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	trace := make([][]int, 0, max+1)
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && from[x] == to[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(from, to, trace, d)
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	lines := make([]Line, 0, n+m)
+	lines = appendLines(lines, OpDelete, from)
+	return appendLines(lines, OpInsert, to)
+}
+
+// backtrack walks the path ending at (len(from), len(to)) after d edits.
+func backtrack(from, to []string, trace [][]int, d int) []Line {
+	lines := make([]Line, 0, len(from)+len(to))
+	x, y := len(from), len(to)
+	for ; d > 0; d-- {
+		prev := trace[d-1]
+		k := x - y
+
+		// the diagonal of the previous edit, prev is indexed from -(d-1)
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			prevK = k + 1
+		}
+		prevX := prev[prevK+d-1]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			lines = append(lines, Line{Op: OpEqual, Text: from[x]})
+		}
+		if prevK == k+1 {
+			lines = append(lines, Line{Op: OpInsert, Text: to[prevY]})
+		} else {
+			lines = append(lines, Line{Op: OpDelete, Text: from[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 {
+		x--
+		lines = append(lines, Line{Op: OpEqual, Text: from[x]})
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+func appendLines(lines []Line, op string, texts []string) []Line {
+	for _, text := range texts {
+		lines = append(lines, Line{Op: op, Text: text})
+	}
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{"both empty", "", "", []Line{}},
+		{"added", "", "a\nb", []Line{{OpInsert, "a"}, {OpInsert, "b"}}},
+		{"removed", "a\nb", "", []Line{{OpDelete, "a"}, {OpDelete, "b"}}},
+		{"equal", "a\nb", "a\nb", []Line{{OpEqual, "a"}, {OpEqual, "b"}}},
+		{"crlf", "a\r\nb", "a\nb", []Line{{OpEqual, "a"}, {OpEqual, "b"}}},
+		{"replaced", "a\nb\nc", "a\nx\nc", []Line{{OpEqual, "a"}, {OpDelete, "b"}, {OpInsert, "x"}, {OpEqual, "c"}}},
+		{"inserted", "a\nc", "a\nb\nc", []Line{{OpEqual, "a"}, {OpInsert, "b"}, {OpEqual, "c"}}},
+		{"moved", "a\nb\nc", "b\nc\na", []Line{{OpDelete, "a"}, {OpEqual, "b"}, {OpEqual, "c"}, {OpInsert, "a"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// lcs is the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] >= cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestLinesIsShortestEditScript(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	text := func() string {
+		lines := make([]string, rnd.Intn(30))
+		for k := range lines {
+			lines[k] = string(rune('a' + rnd.Intn(4)))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	for k := 0; k < 500; k++ {
+		a, b := text(), text()
+		var from, to []string
+		equal := 0
+		for _, line := range Lines(a, b) {
+			switch line.Op {
+			case OpEqual:
+				from, to = append(from, line.Text), append(to, line.Text)
+				equal++
+			case OpDelete:
+				from = append(from, line.Text)
+			case OpInsert:
+				to = append(to, line.Text)
+			}
+		}
+		if strings.Join(from, "\n") != a || strings.Join(to, "\n") != b {
+			t.Fatalf("Lines(%q, %q) does not rebuild both texts", a, b)
+		}
+		if want := lcs(splitLines(a), splitLines(b)); equal != want {
+			t.Fatalf("Lines(%q, %q) keeps %d lines, want %d", a, b, equal, want)
+		}
+	}
+}
+
+func TestLinesBoundsLargeBodies(t *testing.T) {
+	// the largest bodies allowed, differing on every line
+	a := strings.Repeat("a\n", 32767)
+	b := strings.Repeat("b\n", 32767)
+
+	start := time.Now()
+	lines := Lines("head\n"+a, "head\n"+b)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Lines() took %v", elapsed)
+	}
+
+	if len(lines) != 1+2*32767+1 {
+		t.Fatalf("%d lines", len(lines))
+	}
+	if lines[0] != (Line{OpEqual, "head"}) || lines[1].Op != OpDelete || lines[32768].Op != OpInsert {
+		t.Errorf("want the head kept, then the old lines removed and the new ones inserted")
+	}
+	if last := lines[len(lines)-1]; last != (Line{OpEqual, ""}) {
+		t.Errorf("last line = %v, want the common empty tail", last)
+	}
+}
@@ -0,0 +1,50 @@
+package helper
+
+import (
+	"article-app/pkg/jwt"
+	"net/http"
+	"strconv"
+)
+
+// GetUserID returns the user id stored as identity in the jwt payload.
+// Numeric claims are decoded as float64, so the identity is normalized
+// through its string form before being converted.
+func GetUserID(jwtAuth jwt.JWT, r *http.Request) (int, error) {
+	identity, err := jwtAuth.GetIdentity(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(jwt.String(identity))
+}
+
+// GetRoles returns the role names carried by the roles claim of the jwt payload.
+func GetRoles(jwtAuth jwt.JWT, r *http.Request) []string {
+	payload, err := jwtAuth.GetPayload(r)
+	if err != nil {
+		return nil
+	}
+
+	claim, ok := payload["roles"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	roles := make([]string, 0, len(claim))
+	for _, v := range claim {
+		roles = append(roles, jwt.String(v))
+	}
+	return roles
+}
+
+// HasRole reports whether the jwt payload carries at least one of the given roles.
+func HasRole(jwtAuth jwt.JWT, r *http.Request, roles ...string) bool {
+	for _, granted := range GetRoles(jwtAuth, r) {
+		for _, role := range roles {
+			if granted == role {
+				return true
+			}
+		}
+	}
+	return false
+}
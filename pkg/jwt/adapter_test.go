@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMemoryAdapterExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewMemoryAdapter().(*memoryAdapter)
+	a.now = func() time.Time { return now }
+
+	a.Put(ctx, "short", "1", time.Minute)
+	a.Put(ctx, "forever", "2", 0)
+
+	now = now.Add(59 * time.Second)
+	if v, _ := a.Get(ctx, "short"); v != "1" {
+		t.Errorf("Get(short) before expiry = %v", v)
+	}
+
+	now = now.Add(time.Second)
+	if v, _ := a.Get(ctx, "short"); v != nil {
+		t.Errorf("Get(short) at expiry = %v, want nil", v)
+	}
+	if v, _ := a.Get(ctx, "forever"); v != "2" {
+		t.Errorf("Get(forever) = %v", v)
+	}
+}
+
+func TestMemoryAdapterSweepsExpiredMarks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewMemoryAdapter().(*memoryAdapter)
+	a.now = func() time.Time { return now }
+
+	a.Put(ctx, "short", "1", time.Second)
+	now = now.Add(memorySweepInterval)
+	a.Put(ctx, "other", "2", time.Second)
+
+	if _, ok := a.items["short"]; ok {
+		t.Error("expired mark kept after a sweep")
+	}
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&IdentityMark{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestDatabaseAdapterExpiry(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	a := NewDatabaseAdapter(db)
+
+	if err := a.Put(ctx, "live", "1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put(ctx, "forever", "2", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put(ctx, "gone", "3", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	db.Model(&IdentityMark{}).Where("identity_key = ?", "gone").Update("expired_at", time.Now().Add(-time.Second))
+
+	for key, want := range map[string]interface{}{"live": "1", "forever": "2", "gone": nil} {
+		if v, err := a.Get(ctx, key); err != nil || v != want {
+			t.Errorf("Get(%s) = %v, %v, want %v", key, v, err, want)
+		}
+	}
+
+	// a new token replaces the mark
+	if err := a.Put(ctx, "live", "4", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := a.Get(ctx, "live"); v != "4" {
+		t.Errorf("Get(live) after Put = %v, want 4", v)
+	}
+
+	if err := a.Purge(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var n int64
+	db.Model(&IdentityMark{}).Count(&n)
+	if n != 2 {
+		t.Errorf("%d marks after Purge, want 2", n)
+	}
+}
+
+func TestDestroyIdentity(t *testing.T) {
+	adapters := map[string]func(t *testing.T) Adapter{
+		"memory":   func(t *testing.T) Adapter { return NewMemoryAdapter() },
+		"database": func(t *testing.T) Adapter { return NewDatabaseAdapter(openTestDB(t)) },
+	}
+	for name, adapter := range adapters {
+		t.Run(name, func(t *testing.T) {
+			auth, err := NewJwt(&Options{
+				SignMethod:  HS256,
+				SecretKey:   "secret",
+				Locations:   "header:Authorization",
+				IdentityKey: "uid",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			auth.SetAdapter(adapter(t))
+
+			token, err := auth.GenerateToken(Payload{"uid": 1}, "issuer", 3600)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+token.Token)
+			if _, err := auth.Middleware(r); err != nil {
+				t.Fatalf("Middleware() before destroy error = %v", err)
+			}
+
+			if err := auth.DestroyIdentity("issuer", 1); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := auth.Middleware(r); !IsInvalidToken(err) {
+				t.Errorf("Middleware() after destroy error = %v, want an invalid token", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdentityMark is the row of an identification mark stored by the database
+// adapter, a mark without ExpiredAt never expires.
+type IdentityMark struct {
+	Key       string     `gorm:"type:varchar(255);column:identity_key;primaryKey"`
+	Value     string     `gorm:"type:varchar(64);column:jid"`
+	ExpiredAt *time.Time `gorm:"column:expired_at;index"`
+}
+
+func (IdentityMark) TableName() string {
+	return "jwt_identities"
+}
+
+// DatabaseAdapter stores the identification marks in the jwt_identities
+// table, so every instance of the app sharing the database sees them.
+type DatabaseAdapter struct {
+	db *gorm.DB
+}
+
+// NewDatabaseAdapter returns an Adapter on db, the expired rows are ignored
+// and removed by Purge.
+func NewDatabaseAdapter(db *gorm.DB) *DatabaseAdapter {
+	return &DatabaseAdapter{db: db}
+}
+
+func (a *DatabaseAdapter) Get(ctx context.Context, key string) (interface{}, error) {
+	var mark IdentityMark
+	err := a.db.WithContext(ctx).Where("(expired_at IS NULL OR expired_at > ?)", time.Now()).First(&mark, "identity_key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mark.Value, nil
+}
+
+// Put stores the mark for timeout, a timeout of 0 never expires like with the
+// other adapters.
+func (a *DatabaseAdapter) Put(ctx context.Context, key string, val interface{}, timeout time.Duration) error {
+	mark := IdentityMark{
+		Key:   key,
+		Value: String(val),
+	}
+	if timeout > 0 {
+		expiredAt := time.Now().Add(timeout)
+		mark.ExpiredAt = &expiredAt
+	}
+	return a.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "identity_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"jid", "expired_at"}),
+	}).Create(&mark).Error
+}
+
+func (a *DatabaseAdapter) Delete(ctx context.Context, key string) error {
+	return a.db.WithContext(ctx).Delete(&IdentityMark{}, "identity_key = ?", key).Error
+}
+
+// Purge removes the expired marks.
+func (a *DatabaseAdapter) Purge(ctx context.Context) error {
+	return a.db.WithContext(ctx).Delete(&IdentityMark{}, "expired_at <= ?", time.Now()).Error
+}
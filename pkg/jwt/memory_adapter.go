@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryAdapter keeps the identification marks in the memory of the process,
+// it suits a single instance of the app. The marks are lost on restart, the
+// tokens issued before then are no longer valid.
+type memoryAdapter struct {
+	mu        sync.Mutex
+	items     map[string]memoryItem
+	now       func() time.Time
+	sweepedAt time.Time
+}
+
+type memoryItem struct {
+	val       interface{}
+	expiredAt time.Time
+}
+
+// memorySweepInterval is how often Put drops the expired marks.
+const memorySweepInterval = time.Minute
+
+// NewMemoryAdapter returns an Adapter storing the marks in a map, each mark
+// expires with its token.
+func NewMemoryAdapter() Adapter {
+	return &memoryAdapter{
+		items: make(map[string]memoryItem),
+		now:   time.Now,
+	}
+}
+
+func (a *memoryAdapter) Get(ctx context.Context, key string) (interface{}, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.items[key]
+	if !ok {
+		return nil, nil
+	}
+	if item.expired(a.now()) {
+		delete(a.items, key)
+		return nil, nil
+	}
+	return item.val, nil
+}
+
+func (a *memoryAdapter) Put(ctx context.Context, key string, val interface{}, timeout time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if now.Sub(a.sweepedAt) >= memorySweepInterval {
+		for k, v := range a.items {
+			if v.expired(now) {
+				delete(a.items, k)
+			}
+		}
+		a.sweepedAt = now
+	}
+
+	item := memoryItem{val: val}
+	if timeout > 0 {
+		item.expiredAt = now.Add(timeout)
+	}
+	a.items[key] = item
+	return nil
+}
+
+func (a *memoryAdapter) Delete(ctx context.Context, key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.items, key)
+	return nil
+}
+
+// expired reports whether the item has expired, an item without expiry never does.
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiredAt.IsZero() && !now.Before(i.expiredAt)
+}
@@ -0,0 +1,430 @@
+package jwt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRedisMaxIdle         = 10
+	defaultRedisMaxConnLifetime = 30 * time.Second
+	defaultRedisIdleTimeout     = 10 * time.Second
+	defaultRedisDialTimeout     = 5 * time.Second
+)
+
+var (
+	// indicates that the redis configuration has no address
+	errRedisAddress = errors.New("redis address is missing")
+
+	// indicates that the adapter has been closed
+	errRedisClosed = errors.New("redis adapter is closed")
+
+	// indicates that no connection was released within the wait timeout
+	errRedisPoolTimeout = errors.New("redis connection pool timeout")
+)
+
+// redisError is an error reply of the server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// RedisAdapter stores the identification marks in redis, so every instance of
+// the app connected to the same server shares them. It speaks the RESP protocol
+// of redis itself and keeps a pool of connections sized by the Config.
+//
+// Addresses joined with ',' are tried in order. With MasterName they are the
+// sentinels asked for the address of the master. A cluster node answering
+// MOVED is followed to the node owning the key. MinIdle is not used, the
+// connections are only dialed when needed.
+type RedisAdapter struct {
+	config    Config
+	addresses []string
+
+	mu       sync.Mutex
+	idle     []*redisConn
+	returned chan struct{}
+	slots    chan struct{}
+	closed   bool
+}
+
+type redisConn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	created  time.Time
+	returned time.Time
+}
+
+// NewRedisAdapter returns an Adapter on the redis server of config, the zero
+// pool settings take the defaults described on Config.
+func NewRedisAdapter(config *Config) (*RedisAdapter, error) {
+	a := &RedisAdapter{config: *config, returned: make(chan struct{})}
+
+	for _, address := range strings.Split(config.Address, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			a.addresses = append(a.addresses, address)
+		}
+	}
+	if len(a.addresses) == 0 {
+		return nil, errRedisAddress
+	}
+
+	if a.config.MaxIdle <= 0 {
+		a.config.MaxIdle = defaultRedisMaxIdle
+	}
+	if a.config.MaxConnLifetime <= 0 {
+		a.config.MaxConnLifetime = defaultRedisMaxConnLifetime
+	}
+	if a.config.IdleTimeout <= 0 {
+		a.config.IdleTimeout = defaultRedisIdleTimeout
+	}
+	if a.config.DialTimeout <= 0 {
+		a.config.DialTimeout = defaultRedisDialTimeout
+	}
+	if a.config.MaxActive > 0 {
+		a.slots = make(chan struct{}, a.config.MaxActive)
+	}
+
+	return a, nil
+}
+
+func (a *RedisAdapter) Get(ctx context.Context, key string) (interface{}, error) {
+	reply, err := a.do(ctx, "GET", key)
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	return String(reply), nil
+}
+
+func (a *RedisAdapter) Put(ctx context.Context, key string, val interface{}, timeout time.Duration) error {
+	args := []string{"SET", key, String(val)}
+	if timeout > 0 {
+		args = append(args, "PX", strconv.FormatInt(timeout.Milliseconds(), 10))
+	}
+	_, err := a.do(ctx, args...)
+	return err
+}
+
+func (a *RedisAdapter) Delete(ctx context.Context, key string) error {
+	_, err := a.do(ctx, "DEL", key)
+	return err
+}
+
+// Close closes the idle connections, the adapter cannot be used afterwards.
+func (a *RedisAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closed = true
+	for _, c := range a.idle {
+		c.conn.Close()
+	}
+	a.idle = nil
+	return nil
+}
+
+// do runs a command on a connection of the pool.
+func (a *RedisAdapter) do(ctx context.Context, args ...string) (interface{}, error) {
+	c, err := a.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := a.command(ctx, c, args...)
+	var replyErr redisError
+	// an error reply leaves the connection usable
+	a.put(c, err != nil && !errors.As(err, &replyErr))
+
+	if errors.As(err, &replyErr) && strings.HasPrefix(string(replyErr), "MOVED ") {
+		return a.moved(ctx, string(replyErr), args...)
+	}
+	return reply, err
+}
+
+// moved runs the command again on the cluster node given by a MOVED reply,
+// which looks like "MOVED 3999 127.0.0.1:6381".
+func (a *RedisAdapter) moved(ctx context.Context, reply string, args ...string) (interface{}, error) {
+	parts := strings.Fields(reply)
+	if len(parts) != 3 {
+		return nil, redisError(reply)
+	}
+
+	c, err := a.dial(ctx, parts[2])
+	if err != nil {
+		return nil, err
+	}
+	defer c.conn.Close()
+
+	return a.command(ctx, c, args...)
+}
+
+// get returns an idle connection or dials a new one. When the pool is limited
+// to MaxActive connections it waits for a connection to be given back or closed.
+func (a *RedisAdapter) get(ctx context.Context) (*redisConn, error) {
+	var timeout <-chan time.Time
+	if a.slots != nil && a.config.WaitTimeout > 0 {
+		timer := time.NewTimer(a.config.WaitTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		now := time.Now()
+
+		a.mu.Lock()
+		if a.closed {
+			a.mu.Unlock()
+			return nil, errRedisClosed
+		}
+		for len(a.idle) > 0 {
+			c := a.idle[len(a.idle)-1]
+			a.idle = a.idle[:len(a.idle)-1]
+			if !a.stale(c, now) {
+				a.mu.Unlock()
+				return c, nil
+			}
+			c.conn.Close()
+			a.release()
+		}
+		returned := a.returned
+		a.mu.Unlock()
+
+		if a.slots != nil {
+			select {
+			case a.slots <- struct{}{}:
+			case <-returned:
+				continue
+			case <-timeout:
+				return nil, errRedisPoolTimeout
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		c, err := a.connect(ctx)
+		if err != nil {
+			a.release()
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+// put gives the connection back to the pool, broken connections and the ones
+// above MaxIdle are closed.
+func (a *RedisAdapter) put(c *redisConn, broken bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if broken || a.closed || len(a.idle) >= a.config.MaxIdle {
+		c.conn.Close()
+		a.release()
+		return
+	}
+	c.returned = time.Now()
+	a.idle = append(a.idle, c)
+
+	// wake up the callers of get waiting for a connection
+	close(a.returned)
+	a.returned = make(chan struct{})
+}
+
+func (a *RedisAdapter) stale(c *redisConn, now time.Time) bool {
+	return now.Sub(c.returned) >= a.config.IdleTimeout || now.Sub(c.created) >= a.config.MaxConnLifetime
+}
+
+func (a *RedisAdapter) release() {
+	if a.slots != nil {
+		<-a.slots
+	}
+}
+
+// connect dials the first address answering, or the master the sentinels
+// point to, then authenticates and selects the database.
+func (a *RedisAdapter) connect(ctx context.Context) (*redisConn, error) {
+	var lastErr error
+	for _, address := range a.addresses {
+		if a.config.MasterName != "" {
+			master, err := a.master(ctx, address)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			address = master
+		}
+
+		c, err := a.dial(ctx, address)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return c, nil
+	}
+	return nil, lastErr
+}
+
+// master asks the sentinel at address for the address of the master.
+func (a *RedisAdapter) master(ctx context.Context, address string) (string, error) {
+	c, err := a.open(ctx, address)
+	if err != nil {
+		return "", err
+	}
+	defer c.conn.Close()
+
+	reply, err := a.command(ctx, c, "SENTINEL", "get-master-addr-by-name", a.config.MasterName)
+	if err != nil {
+		return "", err
+	}
+	parts, ok := reply.([]interface{})
+	if !ok || len(parts) != 2 {
+		return "", fmt.Errorf("redis: sentinel %s does not know master %s", address, a.config.MasterName)
+	}
+	return net.JoinHostPort(String(parts[0]), String(parts[1])), nil
+}
+
+// dial opens a connection to the redis server at address ready for commands.
+func (a *RedisAdapter) dial(ctx context.Context, address string) (*redisConn, error) {
+	c, err := a.open(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.config.Pass != "" {
+		if _, err = a.command(ctx, c, "AUTH", a.config.Pass); err != nil {
+			c.conn.Close()
+			return nil, err
+		}
+	}
+	if a.config.Db != 0 {
+		if _, err = a.command(ctx, c, "SELECT", strconv.Itoa(a.config.Db)); err != nil {
+			c.conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// open dials address, over TLS when configured.
+func (a *RedisAdapter) open(ctx context.Context, address string) (*redisConn, error) {
+	dialer := &net.Dialer{Timeout: a.config.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.config.TLS || a.config.TLSConfig != nil {
+		config := a.config.TLSConfig
+		if config == nil {
+			host, _, _ := net.SplitHostPort(address)
+			config = &tls.Config{ServerName: host, InsecureSkipVerify: a.config.TLSSkipVerify}
+		}
+		conn = tls.Client(conn, config)
+	}
+
+	now := time.Now()
+	return &redisConn{conn: conn, reader: bufio.NewReader(conn), created: now, returned: now}, nil
+}
+
+// command writes args as a RESP array and reads the reply. The read and write
+// deadlines come from the Config, or from ctx when it ends earlier.
+func (a *RedisAdapter) command(ctx context.Context, c *redisConn, args ...string) (interface{}, error) {
+	if err := c.conn.SetWriteDeadline(a.deadline(ctx, a.config.WriteTimeout)); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	if err := c.conn.SetReadDeadline(a.deadline(ctx, a.config.ReadTimeout)); err != nil {
+		return nil, err
+	}
+	return readRedisReply(c.reader)
+}
+
+// deadline returns the earliest of now+timeout and the deadline of ctx, zero
+// when there is none.
+func (a *RedisAdapter) deadline(ctx context.Context, timeout time.Duration) time.Time {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	return deadline
+}
+
+// readRedisReply reads a RESP reply: a string, an int64, nil, a redisError or
+// a []interface{} of those.
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, line := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return line, nil
+	case '-':
+		return nil, redisError(line)
+	case ':':
+		return strconv.ParseInt(line, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return string(b[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			// an error inside an array is kept as an item
+			item, err := readRedisReply(r)
+			var replyErr redisError
+			if errors.As(err, &replyErr) {
+				items[i] = replyErr
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+}
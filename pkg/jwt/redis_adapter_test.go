@@ -0,0 +1,265 @@
+package jwt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis answers the commands of the adapter over a net.Listener: AUTH,
+// SELECT, SET with PX, GET, DEL and SENTINEL get-master-addr-by-name.
+type fakeRedis struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	data     map[string]fakeRedisItem
+	commands []string
+	conns    []net.Conn
+	dials    int
+	// master is answered to SENTINEL, moved to every key command when set
+	master string
+	moved  string
+}
+
+type fakeRedisItem struct {
+	val       string
+	expiredAt time.Time
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeRedis{listener: l, data: make(map[string]fakeRedisItem)}
+	t.Cleanup(func() {
+		l.Close()
+		s.drop()
+	})
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.dials++
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedis) addr() string {
+	return s.listener.Addr().String()
+}
+
+// drop closes the connections of the clients, like a restarted server.
+func (s *fakeRedis) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		// a command is a RESP array of bulk strings, like a reply
+		reply, err := readRedisReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, v := range reply.([]interface{}) {
+			args = append(args, v.(string))
+		}
+		fmt.Fprint(conn, s.answer(args))
+	}
+}
+
+func (s *fakeRedis) answer(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = append(s.commands, strings.Join(args, " "))
+	switch cmd := strings.ToUpper(args[0]); {
+	case s.moved != "" && (cmd == "SET" || cmd == "GET" || cmd == "DEL"):
+		return "-MOVED 1234 " + s.moved + "\r\n"
+	case cmd == "AUTH" || cmd == "SELECT":
+		return "+OK\r\n"
+	case cmd == "SENTINEL":
+		host, port, _ := net.SplitHostPort(s.master)
+		return fmt.Sprintf("*2\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(host), host, len(port), port)
+	case cmd == "SET":
+		item := fakeRedisItem{val: args[2]}
+		if len(args) == 5 && args[3] == "PX" {
+			ms, _ := strconv.Atoi(args[4])
+			item.expiredAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		s.data[args[1]] = item
+		return "+OK\r\n"
+	case cmd == "GET":
+		item, ok := s.data[args[1]]
+		if !ok || !item.expiredAt.IsZero() && time.Now().After(item.expiredAt) {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(item.val), item.val)
+	case cmd == "DEL":
+		delete(s.data, args[1])
+		return ":1\r\n"
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+func (s *fakeRedis) sent(command string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.commands {
+		if v == command {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *fakeRedis) dialCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dials
+}
+
+func newTestRedisAdapter(t *testing.T, config Config) *RedisAdapter {
+	t.Helper()
+	a, err := NewRedisAdapter(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+func TestRedisAdapterPutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis(t)
+	a := newTestRedisAdapter(t, Config{Address: server.addr(), Pass: "secret", Db: 2})
+
+	if err := a.Put(ctx, "key", "jid", 1500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put(ctx, "forever", "jid", 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, command := range []string{"AUTH secret", "SELECT 2", "SET key jid PX 1500", "SET forever jid"} {
+		if !server.sent(command) {
+			t.Errorf("%q not sent", command)
+		}
+	}
+
+	if v, err := a.Get(ctx, "key"); err != nil || v != "jid" {
+		t.Errorf("Get() = %v, %v, want jid", v, err)
+	}
+	if err := a.Delete(ctx, "key"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := a.Get(ctx, "key"); err != nil || v != nil {
+		t.Errorf("Get() after Delete = %v, %v, want nil", v, err)
+	}
+
+	if err := a.Put(ctx, "short", "jid", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if v, err := a.Get(ctx, "short"); err != nil || v != nil {
+		t.Errorf("Get() after the TTL = %v, %v, want nil", v, err)
+	}
+
+	if n := server.dialCount(); n != 1 {
+		t.Errorf("%d connections dialed, want the first one reused", n)
+	}
+}
+
+func TestRedisAdapterReplacesBrokenConnection(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis(t)
+	a := newTestRedisAdapter(t, Config{Address: server.addr(), MaxActive: 1, WaitTimeout: time.Second})
+
+	if err := a.Put(ctx, "key", "jid", 0); err != nil {
+		t.Fatal(err)
+	}
+	server.drop()
+
+	// the idle connection is broken, the command fails and the connection is dropped
+	if _, err := a.Get(ctx, "key"); err == nil {
+		t.Fatal("Get() on a broken connection succeeded")
+	}
+	// its slot is given back, a new connection is dialed and kept
+	for k := 0; k < 2; k++ {
+		if v, err := a.Get(ctx, "key"); err != nil || v != "jid" {
+			t.Fatalf("Get() = %v, %v, want jid", v, err)
+		}
+	}
+	if n := server.dialCount(); n != 2 {
+		t.Errorf("%d connections dialed, want 2", n)
+	}
+}
+
+func TestRedisAdapterSentinel(t *testing.T) {
+	ctx := context.Background()
+	master := newFakeRedis(t)
+	sentinel := newFakeRedis(t)
+	sentinel.master = master.addr()
+
+	// the first sentinel is down, the next one is asked
+	down, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down.Close()
+
+	a := newTestRedisAdapter(t, Config{Address: down.Addr().String() + "," + sentinel.addr(), MasterName: "mymaster"})
+	if err := a.Put(ctx, "key", "jid", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if !sentinel.sent("SENTINEL get-master-addr-by-name mymaster") {
+		t.Error("master not asked to the sentinel")
+	}
+	if !master.sent("SET key jid") || sentinel.sent("SET key jid") {
+		t.Error("SET not sent to the master")
+	}
+}
+
+func TestRedisAdapterMoved(t *testing.T) {
+	ctx := context.Background()
+	owner := newFakeRedis(t)
+	node := newFakeRedis(t)
+	node.moved = owner.addr()
+
+	a := newTestRedisAdapter(t, Config{Address: node.addr()})
+	if err := a.Put(ctx, "key", "jid", 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := a.Get(ctx, "key"); err != nil || v != "jid" {
+		t.Errorf("Get() = %v, %v, want jid", v, err)
+	}
+	if !owner.sent("SET key jid") {
+		t.Error("SET not sent to the node owning the key")
+	}
+}
@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileMailer appends the messages to a file instead of sending them, for local
+// runs. Without a path the messages are written to the log. The messages are
+// kept whole, tokens included, so it must not be used in production.
+type FileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+// NewFileMailer returns a Mailer writing the messages to path, from is the
+// sender of the messages without one.
+func NewFileMailer(path, from string) *FileMailer {
+	return &FileMailer{
+		path: path,
+		from: from,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	sender, recipients, err := msg.addresses(m.from)
+	if err != nil {
+		return err
+	}
+	data, err := msg.bytes(sender, recipients, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if m.path == "" {
+		log.Printf("mail:\n%s", data)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err = os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
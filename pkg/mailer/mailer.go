@@ -0,0 +1,81 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer sends the emails of the app.
+//
+//	m := mailer.NewFileMailer("data/mail.log")
+//	err := m.Send(ctx, mailer.Message{To: []string{"user@mail.com"}, Subject: "...", Body: "..."})
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// addresses parses the sender and the recipients of msg, from is the sender
+// when msg has none.
+func (msg Message) addresses(from string) (*mail.Address, []*mail.Address, error) {
+	if msg.From != "" {
+		from = msg.From
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mailer: invalid sender %q: %w", from, err)
+	}
+	if len(msg.To) == 0 {
+		return nil, nil, errors.New("mailer: message has no recipient")
+	}
+
+	recipients := make([]*mail.Address, len(msg.To))
+	for k, v := range msg.To {
+		if recipients[k], err = mail.ParseAddress(v); err != nil {
+			return nil, nil, fmt.Errorf("mailer: invalid recipient %q: %w", v, err)
+		}
+	}
+	return sender, recipients, nil
+}
+
+// bytes formats msg as a RFC 5322 message, the addresses are parsed so the
+// headers cannot be injected.
+func (msg Message) bytes(sender *mail.Address, recipients []*mail.Address, now time.Time) ([]byte, error) {
+	to := make([]string, len(recipients))
+	for k, v := range recipients {
+		to[k] = v.String()
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := w.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes(), nil
+}
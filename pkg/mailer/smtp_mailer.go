@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig is the server the SMTP mailer sends through.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender of the messages without one
+	From string
+	// TLS connects with TLS, usually on port 465. Without it the connection
+	// is upgraded with STARTTLS when the server offers it.
+	TLS           bool
+	TLSSkipVerify bool
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer returns a Mailer sending through an SMTP server, the
+// connection is authenticated when a username is set.
+func NewSMTPMailer(config SMTPConfig) Mailer {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &smtpMailer{
+		config: config,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	sender, recipients, err := msg.addresses(m.config.From)
+	if err != nil {
+		return err
+	}
+	data, err := msg.bytes(sender, recipients, time.Now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: m.config.Host, InsecureSkipVerify: m.config.TLSSkipVerify}
+	if m.config.TLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok && !m.config.TLS {
+		if err = c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if m.config.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = c.Mail(sender.Address); err != nil {
+		return err
+	}
+	for _, v := range recipients {
+		if err = c.Rcpt(v.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,205 @@
+package migration
+
+import (
+	"article-app/internal/domain"
+	"article-app/pkg/slug"
+
+	"gorm.io/gorm"
+)
+
+// Migrate runs the data migrations that AutoMigrate cannot express.
+// Every step is idempotent so it is safe to run on each start.
+func Migrate(db *gorm.DB) error {
+	steps := []func(db *gorm.DB) error{
+		backfillArticleAuthor,
+		seedRolePermissions,
+		assignInitialRoles,
+		backfillArticleStatus,
+		backfillArticleRevisions,
+		backfillArticleSlugs,
+		addArticleFulltextIndex,
+		backfillEmailVerification,
+	}
+
+	for _, step := range steps {
+		if err := step(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillArticleAuthor links articles created before the author_id foreign key
+// by matching the legacy free-text author column to user emails. The legacy
+// column is dropped once every article has been linked to a user.
+func backfillArticleAuthor(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&domain.Article{}, "author") {
+		return nil
+	}
+
+	err := db.Exec(`UPDATE articles
+		JOIN users ON users.email = articles.author
+		SET articles.author_id = users.id
+		WHERE articles.author_id IS NULL`).Error
+	if err != nil {
+		return err
+	}
+
+	var unlinked int64
+	if err := db.Model(&domain.Article{}).Unscoped().Where("author_id IS NULL").Count(&unlinked).Error; err != nil {
+		return err
+	}
+	if unlinked > 0 {
+		// keep the legacy column so the remaining rows can be reconciled manually
+		return nil
+	}
+
+	return db.Migrator().DropColumn(&domain.Article{}, "author")
+}
+
+// seedRolePermissions makes sure every default role exists with at least the
+// permissions of domain.DefaultRolePermissions.
+func seedRolePermissions(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for roleName, permissionNames := range domain.DefaultRolePermissions {
+			role := domain.Role{Name: roleName}
+			if err := tx.Where(&role).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+
+			permissions := make([]domain.Permission, len(permissionNames))
+			for k, name := range permissionNames {
+				permissions[k] = domain.Permission{Name: name}
+				if err := tx.Where(&permissions[k]).FirstOrCreate(&permissions[k]).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Model(&role).Association("Permissions").Append(permissions); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// assignInitialRoles gives every user a role the first time RBAC is enabled.
+// Users flagged by the legacy users.is_admin column become admins, all others
+// keep their former rights as authors. The legacy column is dropped afterwards.
+func assignInitialRoles(db *gorm.DB) error {
+	var assigned int64
+	if err := db.Table("user_roles").Count(&assigned).Error; err != nil {
+		return err
+	}
+
+	hasAdminFlag := db.Migrator().HasColumn(&domain.User{}, "is_admin")
+	if assigned > 0 && !hasAdminFlag {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if assigned == 0 && hasAdminFlag {
+			err := tx.Exec(`INSERT INTO user_roles (user_id, role_id)
+				SELECT users.id, roles.id FROM users JOIN roles ON roles.name = ?
+				WHERE users.is_admin = TRUE`, domain.RoleAdmin).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if assigned == 0 {
+			err := tx.Exec(`INSERT INTO user_roles (user_id, role_id)
+				SELECT users.id, roles.id FROM users JOIN roles ON roles.name = ?
+				WHERE NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id)`, domain.RoleAuthor).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if hasAdminFlag {
+			return tx.Migrator().DropColumn(&domain.User{}, "is_admin")
+		}
+		return nil
+	})
+}
+
+// backfillArticleStatus marks articles created before the publishing workflow as
+// published, since they went live the moment they were created.
+func backfillArticleStatus(db *gorm.DB) error {
+	return db.Exec(`UPDATE articles
+		SET status = ?, published_at = created_at
+		WHERE status IS NULL OR status = ''`, domain.ArticleStatusPublished).Error
+}
+
+// backfillArticleRevisions records the current content of articles written
+// before revision history existed as their first revision.
+func backfillArticleRevisions(db *gorm.DB) error {
+	return db.Exec(`INSERT INTO article_revisions (article_id, revision, editor_id, old_title, new_title, old_body, new_body, created_at)
+		SELECT articles.id, 1, articles.author_id, '', articles.title, '', articles.body, articles.updated_at FROM articles
+		WHERE NOT EXISTS (SELECT 1 FROM article_revisions WHERE article_revisions.article_id = articles.id)`).Error
+}
+
+// backfillArticleSlugs gives articles created before slugs existed a unique
+// slug derived from their title.
+func backfillArticleSlugs(db *gorm.DB) error {
+	var articles []domain.Article
+	err := db.Unscoped().Select("id", "title").Where("slug IS NULL OR slug = ''").Order("id").Find(&articles).Error
+	if err != nil || len(articles) == 0 {
+		return err
+	}
+
+	var used []string
+	if err = db.Unscoped().Model(&domain.Article{}).Where("slug <> ''").Pluck("slug", &used).Error; err != nil {
+		return err
+	}
+	var retired []string
+	if err = db.Model(&domain.ArticleSlug{}).Pluck("slug", &retired).Error; err != nil {
+		return err
+	}
+
+	taken := make(map[string]bool, len(used)+len(retired))
+	for _, v := range append(used, retired...) {
+		taken[v] = true
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, article := range articles {
+			base := slug.Make(article.Title)
+			if base == "" {
+				base = "article"
+			}
+
+			candidate := base
+			for n := 2; taken[candidate]; n++ {
+				candidate = slug.WithSuffix(base, n)
+			}
+			taken[candidate] = true
+
+			err := tx.Model(&domain.Article{}).Unscoped().Where("id = ?", article.ID).UpdateColumn("slug", candidate).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// addArticleFulltextIndex creates the FULLTEXT index searched by the article
+// list. Only MySQL has one, the other databases use an embedded index.
+func addArticleFulltextIndex(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" || db.Migrator().HasIndex(&domain.Article{}, "idx_articles_fulltext") {
+		return nil
+	}
+	return db.Exec("ALTER TABLE articles ADD FULLTEXT INDEX idx_articles_fulltext (title, body)").Error
+}
+
+// backfillEmailVerification marks the users created before registration as
+// verified. Register stores a user with its verification token in the same
+// transaction and the tokens are never deleted, so a registered user always
+// has one.
+func backfillEmailVerification(db *gorm.DB) error {
+	return db.Exec(`UPDATE users
+		SET email_verified_at = created_at
+		WHERE email_verified_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM user_tokens WHERE user_tokens.user_id = users.id AND user_tokens.purpose = ?)`, domain.UserTokenEmailVerification).Error
+}
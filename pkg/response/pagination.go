@@ -0,0 +1,111 @@
+package response
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"article-app/pkg/database/paginator"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/beego/beego/v2/server/web/context"
+)
+
+// Pagination is the metadata of a list response. Page and TotalPages are set
+// by page based lists, the cursors by cursor based ones.
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	Total      *int64 `json:"total,omitempty"`
+	TotalPages int64  `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// PagePagination reads the metadata of a page based list.
+func PagePagination[T any](p *paginator.Page[T]) Pagination {
+	total := p.Total
+	return Pagination{
+		Page:       p.CurrentPage,
+		PageSize:   p.PageSize,
+		Total:      &total,
+		TotalPages: p.MaxPage,
+	}
+}
+
+// CursorPagination reads the metadata of a cursor based list, the total is
+// only known when it was counted.
+func CursorPagination[T any](p *paginator.CursorPage[T]) Pagination {
+	return Pagination{
+		PageSize:   p.PageSize,
+		Total:      p.Total,
+		NextCursor: p.NextCursor,
+		PrevCursor: p.PrevCursor,
+	}
+}
+
+// OkWithPagination sends data as a list with its pagination metadata. The
+// neighbour pages are also sent as RFC 8288 Link header and the total as
+// X-Total-Count header, the links keep the other query params of the request.
+//
+//	result, err := h.TagUseCase.GetTags(h.Ctx, page, limit, sort)
+//	...
+//	h.OkWithPagination(h.Ctx, h.Tr("message.success"), result.Records, response.PagePagination(result))
+func (r ApiResponse) OkWithPagination(ctx *context.Context, message string, data interface{}, pagination Pagination) error {
+	if links := paginationLinks(ctx.Request.URL, pagination); links != "" {
+		ctx.Output.Header("Link", links)
+	}
+	if pagination.Total != nil {
+		ctx.Output.Header("X-Total-Count", strconv.FormatInt(*pagination.Total, 10))
+	}
+	ctx.Output.SetStatus(http.StatusOK)
+
+	return ctx.Output.JSON(ApiResponse{
+		Code:       http.StatusText(http.StatusOK),
+		RequestId:  ctx.ResponseWriter.ResponseWriter.Header().Get("X-REQUEST-ID"),
+		Message:    message,
+		Data:       data,
+		Pagination: &pagination,
+		TimeStamp:  time.Now().Format("2006-01-02 15:04:05"),
+	}, beego.BConfig.RunMode != "prod", false)
+}
+
+// paginationLinks renders the first, prev, next and last links, a cursor
+// based list has no last link as its last cursor is unknown.
+func paginationLinks(u *url.URL, pagination Pagination) string {
+	var links []string
+	link := func(rel, param, value string) {
+		query := u.Query()
+		query.Set(param, value)
+		target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		links = append(links, "<"+target.String()+">; rel=\""+rel+"\"")
+	}
+
+	if pagination.Page > 0 {
+		link("first", "page", "1")
+		if pagination.Page > 1 {
+			// past the end the previous page is the last one that has records
+			prev := int64(pagination.Page - 1)
+			if prev > pagination.TotalPages {
+				prev = pagination.TotalPages
+			}
+			link("prev", "page", strconv.FormatInt(prev, 10))
+		}
+		if int64(pagination.Page) < pagination.TotalPages {
+			link("next", "page", strconv.Itoa(pagination.Page+1))
+		}
+		link("last", "page", strconv.FormatInt(pagination.TotalPages, 10))
+		return strings.Join(links, ", ")
+	}
+
+	link("first", "cursor", "")
+	if pagination.PrevCursor != "" {
+		link("prev", "cursor", pagination.PrevCursor)
+	}
+	if pagination.NextCursor != "" {
+		link("next", "cursor", pagination.NextCursor)
+	}
+	return strings.Join(links, ", ")
+}
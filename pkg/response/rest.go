@@ -1,7 +1,6 @@
 package response
 
 import (
-	"fmt"
 	"net/http"
 	"time"
 
@@ -10,12 +9,13 @@ import (
 )
 
 type ApiResponse struct {
-	Code      string      `json:"code"`
-	Message   string      `json:"message"`
-	Data      interface{} `json:"data"`
-	Errors    []Errors    `json:"errors"`
-	RequestId string      `json:"request_id"`
-	TimeStamp string      `json:"time_stamp"`
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Errors     []Errors    `json:"errors"`
+	RequestId  string      `json:"request_id"`
+	TimeStamp  string      `json:"time_stamp"`
 }
 
 type Errors struct {
@@ -39,13 +39,12 @@ func (r ApiResponse) ResponseError(ctx *context.Context, httpStatus int, errorCo
 }
 
 func (r ApiResponse) ResponseErrorWithData(ctx *context.Context, httpStatus int, errorCode string, message string, err error, data interface{}) error {
-	fmt.Println(message)
 	var apiResponse ApiResponse
 	var errorValidations []Errors = nil
 
 	ctx.Output.SetStatus(httpStatus)
 
-	apiResponse.RequestId = ctx.Input.Header("X-REQUEST-ID")
+	apiResponse.RequestId = ctx.ResponseWriter.ResponseWriter.Header().Get("X-REQUEST-ID")
 	apiResponse.Code = errorCode
 	apiResponse.Message = message
 	apiResponse.TimeStamp = time.Now().Format("2006-01-02 15:04:05")
@@ -55,6 +54,20 @@ func (r ApiResponse) ResponseErrorWithData(ctx *context.Context, httpStatus int,
 	return ctx.Output.JSON(apiResponse, beego.BConfig.RunMode != "prod", false)
 }
 
+// ResponseValidationError answers a request rejected by the validation, errs
+// lists the message of every invalid field.
+func (r ApiResponse) ResponseValidationError(ctx *context.Context, httpStatus int, errorCode string, message string, errs []Errors) error {
+	ctx.Output.SetStatus(httpStatus)
+
+	return ctx.Output.JSON(ApiResponse{
+		Code:      errorCode,
+		RequestId: ctx.ResponseWriter.ResponseWriter.Header().Get("X-REQUEST-ID"),
+		Message:   message,
+		Errors:    errs,
+		TimeStamp: time.Now().Format("2006-01-02 15:04:05"),
+	}, beego.BConfig.RunMode != "prod", false)
+}
+
 func (r ApiResponse) Ok(ctx *context.Context, message string, data interface{}) error {
 	ctx.Output.SetStatus(http.StatusOK)
 
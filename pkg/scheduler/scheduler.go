@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Job is the unit of work run on every tick. The context is cancelled when the
+// scheduler stops.
+type Job func(ctx context.Context)
+
+// Scheduler runs a job periodically in a background goroutine.
+//
+//	s := scheduler.New(time.Minute, func(ctx context.Context) { ... })
+//	s.Start()
+//	beego.BeeApp.Server.RegisterOnShutdown(s.Stop)
+type Scheduler struct {
+	interval time.Duration
+	job      Job
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// New create a new Scheduler running job every interval. An interval of zero or
+// less disables the scheduler, Start and Stop do nothing.
+func New(interval time.Duration, job Job) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		job:      job,
+	}
+}
+
+// Start runs the job once immediately and then on every tick until Stop is called.
+func (s *Scheduler) Start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.job(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the running job and waits for the goroutine to exit.
+func (s *Scheduler) Stop() {
+	s.once.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.wg.Wait()
+	})
+}
@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchedulerRunsUntilStop(t *testing.T) {
+	var runs int32
+	ticked := make(chan struct{}, 1)
+	s := New(time.Millisecond, func(ctx context.Context) {
+		if atomic.AddInt32(&runs, 1) == 3 {
+			ticked <- struct{}{}
+		}
+	})
+	s.Start()
+
+	select {
+	case <-ticked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run on the ticks")
+	}
+	s.Stop()
+
+	stopped := atomic.LoadInt32(&runs)
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadInt32(&runs); got != stopped {
+		t.Errorf("job ran %d times after Stop", got-stopped)
+	}
+	// a second Stop is a no-op
+	s.Stop()
+}
+
+func TestSchedulerRunsOnStart(t *testing.T) {
+	ran := make(chan struct{})
+	s := New(time.Hour, func(ctx context.Context) { close(ran) })
+	s.Start()
+	defer s.Stop()
+
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run on Start")
+	}
+}
+
+func TestSchedulerStopCancelsJob(t *testing.T) {
+	started := make(chan struct{})
+	var cancelled int32
+	s := New(time.Hour, func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		atomic.StoreInt32(&cancelled, 1)
+	})
+	s.Start()
+	<-started
+	s.Stop()
+
+	if atomic.LoadInt32(&cancelled) != 1 {
+		t.Error("Stop returned before the job saw the cancellation")
+	}
+}
+
+func TestSchedulerDisabled(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		var runs int32
+		s := New(interval, func(ctx context.Context) { atomic.AddInt32(&runs, 1) })
+		s.Start()
+		time.Sleep(10 * time.Millisecond)
+		s.Stop()
+
+		if runs != 0 {
+			t.Errorf("interval %v: job ran %d times", interval, runs)
+		}
+	}
+}
+
+func TestSchedulerStopWithoutStart(t *testing.T) {
+	s := New(time.Second, func(ctx context.Context) {})
+	s.Stop()
+}
@@ -0,0 +1,161 @@
+package search
+
+// Language knows the stopwords and the stemming of a language.
+type Language struct {
+	Name      string
+	Stopwords map[string]bool
+	Stem      func(word string) string
+}
+
+var (
+	English    = &Language{Name: "en", Stopwords: englishStopwords, Stem: stemEnglish}
+	Indonesian = &Language{Name: "id", Stopwords: indonesianStopwords, Stem: stemIndonesian}
+
+	languages = map[string]*Language{English.Name: English, Indonesian.Name: Indonesian}
+)
+
+// LookupLanguages returns the known languages among names, such as the "en|id"
+// locales of the app, unknown names are skipped.
+func LookupLanguages(names ...string) []*Language {
+	var res []*Language
+	for _, name := range names {
+		if language, ok := languages[name]; ok {
+			res = append(res, language)
+		}
+	}
+	return res
+}
+
+// Analyzer turns text into the words stored in an index. The zero Analyzer
+// only lower cases the words.
+type Analyzer struct {
+	Languages []*Language
+}
+
+func (a Analyzer) names() []string {
+	res := make([]string, len(a.Languages))
+	for k, language := range a.Languages {
+		res[k] = language.Name
+	}
+	return res
+}
+
+// Detect guesses the language of words from the stopwords it uses, the first
+// language wins a tie. It returns nil when the analyzer has no language.
+func (a Analyzer) Detect(words []string) *Language {
+	var best *Language
+	bestHits := -1
+	for _, language := range a.Languages {
+		hits := 0
+		for _, word := range words {
+			if language.Stopwords[word] {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = language, hits
+		}
+	}
+	return best
+}
+
+// Analyze drops the stopwords of the language of words and stems the others.
+func (a Analyzer) Analyze(words []string) []string {
+	return analyzeAs(a.Detect(words), words)
+}
+
+func analyzeAs(language *Language, words []string) []string {
+	if language == nil {
+		return words
+	}
+
+	res := make([]string, 0, len(words))
+	for _, word := range words {
+		if !language.Stopwords[word] {
+			res = append(res, language.Stem(word))
+		}
+	}
+	return res
+}
+
+// Forms returns the stems of a query word in every language, as the language
+// of a short query cannot be told. A stopword of any language has no form.
+func (a Analyzer) Forms(word string) []string {
+	if len(a.Languages) == 0 {
+		return []string{word}
+	}
+
+	var res []string
+	for _, language := range a.Languages {
+		if language.Stopwords[word] {
+			return nil
+		}
+		stem := language.Stem(word)
+		if !contains(res, stem) {
+			res = append(res, stem)
+		}
+	}
+	return res
+}
+
+// prefix returns the shortest form of a prefix term, a stem is often shorter
+// than the word, e.g. "generic*" has to match the stem "gener".
+func (a Analyzer) prefix(word string) string {
+	res := word
+	for _, language := range a.Languages {
+		if stem := language.Stem(word); len(stem) < len(res) && len(stem) > 0 {
+			res = stem
+		}
+	}
+	return res
+}
+
+// Matcher reports whether a word of a text matches one of the terms q looks
+// for, the word matches when it shares a stem with a term.
+func (a Analyzer) Matcher(q Query) func(word string) bool {
+	exact := make(map[string]bool)
+	var prefixes []string
+	for _, term := range q.Highlights() {
+		if term.Prefix {
+			prefixes = append(prefixes, a.prefix(term.Word))
+			continue
+		}
+		for _, form := range a.Forms(term.Word) {
+			exact[form] = true
+		}
+	}
+
+	return func(word string) bool {
+		for _, form := range a.forms(word) {
+			if exact[form] {
+				return true
+			}
+			for _, prefix := range prefixes {
+				if len(form) >= len(prefix) && form[:len(prefix)] == prefix {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// forms is Forms without dropping the stopwords.
+func (a Analyzer) forms(word string) []string {
+	res := []string{word}
+	for _, language := range a.Languages {
+		if stem := language.Stem(word); !contains(res, stem) {
+			res = append(res, stem)
+		}
+	}
+	return res
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStemEnglish(t *testing.T) {
+	tests := map[string]string{
+		"generics":   "gener",
+		"generic":    "gener",
+		"running":    "run",
+		"hopping":    "hop",
+		"caresses":   "caress",
+		"ponies":     "poni",
+		"relational": "relat",
+		"happiness":  "happi",
+		"agreed":     "agre",
+		"sky":        "sky",
+		"go":         "go",
+	}
+	for word, want := range tests {
+		if got := stemEnglish(word); got != want {
+			t.Errorf("stemEnglish(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestStemIndonesian(t *testing.T) {
+	tests := map[string]string{
+		"pembelajaran":  "ajar",
+		"pelajaran":     "ajar",
+		"menuliskannya": "tulis",
+		"membaca":       "baca",
+		"terbaca":       "baca",
+		"bacalah":       "baca",
+		"dimakan":       "makan",
+		"makan":         "makan",
+		"bukunya":       "buku",
+		"kesempatan":    "sempat",
+		"berlari":       "lari",
+	}
+	for word, want := range tests {
+		if got := stemIndonesian(word); got != want {
+			t.Errorf("stemIndonesian(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestAnalyzerAnalyze(t *testing.T) {
+	analyzer := Analyzer{Languages: LookupLanguages("en", "id")}
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"The generics of the Go language", []string{"gener", "go", "languag"}},
+		{"Saya sedang membaca buku yang menarik", []string{"baca", "buku", "tarik"}},
+	}
+	for _, tt := range tests {
+		if got := analyzer.Analyze(Tokenize(tt.text)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Analyze(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	// without a language the words are only lower cased
+	if got := (Analyzer{}).Analyze(Tokenize("The Generics")); !reflect.DeepEqual(got, []string{"the", "generics"}) {
+		t.Errorf("Analyze() without language = %v", got)
+	}
+}
+
+func TestAnalyzerForms(t *testing.T) {
+	analyzer := Analyzer{Languages: LookupLanguages("en", "id")}
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"the", nil},
+		{"yang", nil},
+		{"running", []string{"run", "running"}},
+		{"membaca", []string{"membaca", "baca"}},
+	}
+	for _, tt := range tests {
+		if got := analyzer.Forms(tt.word); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Forms(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+	if got := analyzer.prefix("generic"); got != "gener" {
+		t.Errorf("prefix(generic) = %q, want gener", got)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	analyzer := Analyzer{Languages: LookupLanguages("en")}
+	text := "Cooking pasta is easy. Boil water, add salt & a generic sauce, then serve it to your guests."
+
+	got := Snippet(text, analyzer.Matcher(ParseQuery("generics", false)), 40)
+	want := "…salt &amp; a <mark>generic</mark> sauce, then serve it to…"
+	if got != want {
+		t.Errorf("Snippet() = %q, want %q", got, want)
+	}
+
+	if got := Snippet(text, analyzer.Matcher(ParseQuery("nothing", false)), 20); got != "Cooking pasta is…" {
+		t.Errorf("Snippet() without match = %q", got)
+	}
+}
@@ -0,0 +1,406 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Field is a text of a document, words of a field with a higher Boost weigh
+// more in the relevance.
+type Field struct {
+	Text  string
+	Boost float64
+}
+
+// Document is what is put in an index. Facets are the values a search can be
+// filtered and counted by, e.g. {"tag": {"1", "4"}}, Stored is handed back as
+// it is with every hit. A document with a lower Version than the indexed one
+// is ignored, so a late write cannot undo a newer one; 0 always replaces.
+type Document struct {
+	ID      int
+	Version int
+	Fields  []Field
+	Facets  map[string][]string
+	Stored  []byte
+}
+
+// Hit is a document matching a query.
+type Hit struct {
+	ID     int
+	Score  float64
+	Stored []byte
+}
+
+// FacetCount is the number of matching documents having a facet value.
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// Options narrows a search. Filters keeps the documents having the value of
+// each facet, Facets lists the facets to count the values of.
+type Options struct {
+	Filters map[string]string
+	Facets  []string
+	Offset  int
+	Limit   int
+}
+
+// Result is a page of the hits of a search, Total counts every hit.
+type Result struct {
+	Total  int
+	Hits   []Hit
+	Facets map[string][]FacetCount
+}
+
+// Index is an in-memory inverted index, it is safe for concurrent use.
+//
+//	index := search.NewIndex(search.Analyzer{Languages: search.LookupLanguages("en", "id")})
+//	index.Put(search.Document{ID: 1, Fields: []search.Field{{Text: "Go generics", Boost: 2}}})
+//	res := index.Search(search.ParseQuery("+generic* -java", true), search.Options{Limit: 10})
+type Index struct {
+	mu       sync.RWMutex
+	analyzer Analyzer
+
+	// postings maps a word to the weighted frequency of the word in each document
+	postings map[string]map[int]float64
+	docs     map[int]*document
+	// dirty is set by a change not saved yet
+	dirty bool
+	// generation counts the changes, a save only clears dirty when no change
+	// happened while it was writing
+	generation uint64
+	// saving serializes the saves, the searches do not wait for them
+	saving sync.Mutex
+	// journal records the writes made during a rebuild, a nil document is a
+	// delete, Replace replays them on the rebuilt content
+	journal map[int]*document
+}
+
+type document struct {
+	version int
+	terms   map[string]float64
+	facets  map[string][]string
+	stored  []byte
+}
+
+func NewIndex(analyzer Analyzer) *Index {
+	return &Index{
+		analyzer: analyzer,
+		postings: make(map[string]map[int]float64),
+		docs:     make(map[int]*document),
+	}
+}
+
+// Analyzer returns the analyzer the words of the index went through.
+func (i *Index) Analyzer() Analyzer {
+	return i.analyzer
+}
+
+// Put indexes the document, replacing its previous content.
+func (i *Index) Put(doc Document) {
+	d := i.analyze(doc)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.put(doc.ID, d) {
+		if i.journal != nil {
+			i.journal[doc.ID] = d
+		}
+		i.changed()
+	}
+}
+
+// analyze weighs the words of doc. The language is detected on all the fields
+// together, a title alone is often too short to tell.
+func (i *Index) analyze(doc Document) *document {
+	fields := make([][]string, len(doc.Fields))
+	var all []string
+	for k, field := range doc.Fields {
+		fields[k] = Tokenize(field.Text)
+		all = append(all, fields[k]...)
+	}
+	language := i.analyzer.Detect(all)
+
+	terms := make(map[string]float64)
+	for k, words := range fields {
+		for _, word := range analyzeAs(language, words) {
+			terms[word] += doc.Fields[k].Boost
+		}
+	}
+	return &document{version: doc.Version, terms: terms, facets: doc.Facets, stored: doc.Stored}
+}
+
+// put reports false when the indexed document is newer than d.
+func (i *Index) put(id int, d *document) bool {
+	if old, ok := i.docs[id]; ok && d.version > 0 && old.version > d.version {
+		return false
+	}
+	i.delete(id)
+	for word, weight := range d.terms {
+		if i.postings[word] == nil {
+			i.postings[word] = make(map[int]float64)
+		}
+		i.postings[word][id] = weight
+	}
+	i.docs[id] = d
+	return true
+}
+
+// Delete removes the document, unknown ids are ignored.
+func (i *Index) Delete(id int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.journal != nil {
+		i.journal[id] = nil
+	}
+	if _, ok := i.docs[id]; ok {
+		i.delete(id)
+		i.changed()
+	}
+}
+
+// changed marks the index as changed, the caller holds the write lock.
+func (i *Index) changed() {
+	i.dirty = true
+	i.generation++
+}
+
+func (i *Index) delete(id int) {
+	d, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for word := range d.terms {
+		delete(i.postings[word], id)
+		if len(i.postings[word]) == 0 {
+			delete(i.postings, word)
+		}
+	}
+	delete(i.docs, id)
+}
+
+// BeginRebuild starts recording the writes to the index, until Replace
+// replays them on the rebuilt content or CancelRebuild drops them. A rebuild
+// reads its source after BeginRebuild, so a write it missed is recorded.
+func (i *Index) BeginRebuild() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.journal = make(map[int]*document)
+}
+
+// CancelRebuild stops recording the writes of a rebuild that failed.
+func (i *Index) CancelRebuild() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.journal = nil
+}
+
+// Replace swaps the content of the index for the content of other, which is
+// how an index rebuilt on the side goes live at once. The writes recorded
+// since BeginRebuild are applied again on top of other.
+func (i *Index) Replace(other *Index) {
+	other.mu.RLock()
+	postings, docs := other.postings, other.docs
+	other.mu.RUnlock()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.postings, i.docs = postings, docs
+	for id, d := range i.journal {
+		if d == nil {
+			i.delete(id)
+		} else {
+			i.put(id, d)
+		}
+	}
+	i.journal = nil
+	i.changed()
+}
+
+// Versions returns the version of each indexed document by id.
+func (i *Index) Versions() map[int]int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	versions := make(map[int]int, len(i.docs))
+	for id, d := range i.docs {
+		versions[id] = d.version
+	}
+	return versions
+}
+
+// Len returns the number of indexed documents.
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.docs)
+}
+
+// Dirty reports whether the index changed since it was last saved or loaded.
+func (i *Index) Dirty() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.dirty
+}
+
+// Search returns the documents matching q and opts, the most relevant first.
+// The relevance is the tf-idf of the matched words.
+func (i *Index) Search(q Query, opts Options) Result {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	candidates := i.match(q)
+	for facet, value := range opts.Filters {
+		for id := range candidates {
+			if !contains(i.docs[id].facets[facet], value) {
+				delete(candidates, id)
+			}
+		}
+	}
+
+	res := Result{Total: len(candidates)}
+	if len(opts.Facets) > 0 {
+		res.Facets = make(map[string][]FacetCount, len(opts.Facets))
+		for _, facet := range opts.Facets {
+			res.Facets[facet] = i.count(facet, candidates)
+		}
+	}
+
+	hits := make([]Hit, 0, len(candidates))
+	for id, score := range candidates {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ID < hits[b].ID
+	})
+
+	if opts.Offset >= len(hits) {
+		return res
+	}
+	hits = hits[opts.Offset:]
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	for k := range hits {
+		hits[k].Stored = i.docs[hits[k].ID].stored
+	}
+	res.Hits = hits
+	return res
+}
+
+// match scores the documents matching q. A term without a form, such as a
+// stopword, is ignored. An empty query matches every document with no score.
+func (i *Index) match(q Query) map[int]float64 {
+	var candidates map[int]float64
+	if q.IsEmpty() {
+		candidates = make(map[int]float64, len(i.docs))
+		for id := range i.docs {
+			candidates[id] = 0
+		}
+	}
+	for _, term := range q.Must {
+		scores, ok := i.score(term)
+		if !ok {
+			continue
+		}
+		if candidates == nil {
+			candidates = scores
+			continue
+		}
+		for id, score := range candidates {
+			if _, ok := scores[id]; ok {
+				candidates[id] = score + scores[id]
+			} else {
+				delete(candidates, id)
+			}
+		}
+	}
+
+	required := candidates != nil || q.IsEmpty()
+	if candidates == nil {
+		candidates = make(map[int]float64)
+	}
+	for _, term := range q.Should {
+		scores, _ := i.score(term)
+		for id, score := range scores {
+			if _, ok := candidates[id]; ok || !required {
+				candidates[id] += score
+			}
+		}
+	}
+	for _, term := range q.Not {
+		scores, _ := i.score(term)
+		for id := range scores {
+			delete(candidates, id)
+		}
+	}
+	return candidates
+}
+
+// score weighs the documents containing a form of term, keeping the best
+// matching word of each document.
+func (i *Index) score(term Term) (map[int]float64, bool) {
+	scores := make(map[int]float64)
+	add := func(word string) {
+		documents := i.postings[word]
+		idf := math.Log(1 + float64(len(i.docs))/float64(len(documents)))
+		for id, weight := range documents {
+			if score := math.Sqrt(weight) * idf; score > scores[id] {
+				scores[id] = score
+			}
+		}
+	}
+
+	if term.Prefix {
+		prefix := i.analyzer.prefix(term.Word)
+		for word := range i.postings {
+			if strings.HasPrefix(word, prefix) {
+				add(word)
+			}
+		}
+		return scores, true
+	}
+
+	forms := i.analyzer.Forms(term.Word)
+	for _, form := range forms {
+		if _, ok := i.postings[form]; ok {
+			add(form)
+		}
+	}
+	return scores, len(forms) > 0
+}
+
+// count tallies the values of facet among the candidates, the most frequent first.
+func (i *Index) count(facet string, candidates map[int]float64) []FacetCount {
+	counts := make(map[string]int)
+	for id := range candidates {
+		for _, value := range i.docs[id].facets[facet] {
+			counts[value]++
+		}
+	}
+
+	res := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		res = append(res, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(res, func(a, b int) bool {
+		if res[a].Count != res[b].Count {
+			return res[a].Count > res[b].Count
+		}
+		return res[a].Value < res[b].Value
+	})
+	return res
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testIndex() *Index {
+	return NewIndex(Analyzer{Languages: LookupLanguages("en", "id")})
+}
+
+func doc(id, version int, text string, tags ...string) Document {
+	return Document{
+		ID:      id,
+		Version: version,
+		Fields:  []Field{{Text: text, Boost: 1}},
+		Facets:  map[string][]string{"tag": tags},
+		Stored:  []byte(text),
+	}
+}
+
+func hitIDs(res Result) []int {
+	ids := []int{}
+	for _, v := range res.Hits {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	index := testIndex()
+	index.Put(doc(1, 1, "Go generics in practice", "go"))
+	index.Put(doc(2, 1, "Java generics differ from Go generics", "java"))
+	index.Put(doc(3, 1, "Cooking pasta", "food"))
+
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+		want  []int
+	}{
+		{"stemmed", "generic", Options{}, []int{2, 1}},
+		{"required and excluded", "+generics -java", Options{}, []int{1}},
+		{"prefix", "gen*", Options{}, []int{2, 1}},
+		{"stopword only", "the", Options{}, []int{}},
+		{"filtered", "generics", Options{Filters: map[string]string{"tag": "go"}}, []int{1}},
+		{"paged", "generics", Options{Offset: 1, Limit: 1}, []int{1}},
+		{"empty matches all", "", Options{}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hitIDs(index.Search(ParseQuery(tt.query, true), tt.opts)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	res := index.Search(ParseQuery("", true), Options{Facets: []string{"tag"}})
+	if want := []FacetCount{{"food", 1}, {"go", 1}, {"java", 1}}; !reflect.DeepEqual(res.Facets["tag"], want) {
+		t.Errorf("facets = %v, want %v", res.Facets["tag"], want)
+	}
+}
+
+func TestIndexPutKeepsNewerVersion(t *testing.T) {
+	index := testIndex()
+	index.Put(doc(1, 2, "rust"))
+	index.Put(doc(1, 1, "java"))
+
+	if got := hitIDs(index.Search(ParseQuery("java", false), Options{})); len(got) != 0 {
+		t.Errorf("an older version replaced the document")
+	}
+	if got := index.Versions(); !reflect.DeepEqual(got, map[int]int{1: 2}) {
+		t.Errorf("Versions() = %v", got)
+	}
+
+	// a version 0 always replaces
+	index.Put(doc(1, 0, "java"))
+	if got := hitIDs(index.Search(ParseQuery("java", false), Options{})); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("version 0 did not replace the document")
+	}
+
+	index.Put(doc(1, 3, "kotlin"))
+	if got := hitIDs(index.Search(ParseQuery("java", false), Options{})); len(got) != 0 {
+		t.Errorf("the words of the replaced document are still indexed")
+	}
+}
+
+func TestIndexReplaceReplaysWritesOfRebuild(t *testing.T) {
+	index := testIndex()
+	index.Put(doc(1, 1, "old one"))
+	index.Put(doc(2, 1, "old two"))
+
+	index.BeginRebuild()
+	// the rebuild read the articles before these writes
+	fresh := testIndex()
+	fresh.Put(doc(1, 1, "fresh one"))
+	fresh.Put(doc(2, 1, "fresh two"))
+	fresh.Put(doc(3, 1, "fresh three"))
+
+	index.Put(doc(2, 2, "updated two"))
+	index.Put(doc(4, 1, "created four"))
+	index.Delete(3)
+	index.Replace(fresh)
+
+	for query, want := range map[string][]int{
+		"fresh":   {1},
+		"updated": {2},
+		"created": {4},
+		"three":   {},
+		"old":     {},
+	} {
+		if got := hitIDs(index.Search(ParseQuery(query, false), Options{})); !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q) = %v, want %v", query, got, want)
+		}
+	}
+
+	// the journal ends with the rebuild
+	index.Put(doc(5, 1, "later"))
+	index.Replace(testIndex())
+	if index.Len() != 0 {
+		t.Errorf("a write after the rebuild was replayed on the next Replace")
+	}
+}
+
+func TestIndexCancelRebuild(t *testing.T) {
+	index := testIndex()
+	index.BeginRebuild()
+	index.Put(doc(1, 1, "kept"))
+	index.CancelRebuild()
+
+	index.Replace(testIndex())
+	if index.Len() != 0 {
+		t.Errorf("a write of a cancelled rebuild was replayed")
+	}
+}
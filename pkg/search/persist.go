@@ -0,0 +1,119 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// snapshotVersion changes with the layout of the snapshot or the analysis of
+// the words, an older snapshot has to be rebuilt.
+const snapshotVersion = 2
+
+// ErrStaleSnapshot is returned by Load for a snapshot written by another
+// version or with other languages, the index has to be rebuilt.
+var ErrStaleSnapshot = errors.New("search: stale index snapshot")
+
+type snapshot struct {
+	Version   int
+	Languages []string
+	Docs      map[int]snapshotDocument
+}
+
+type snapshotDocument struct {
+	Version int
+	Terms   map[string]float64
+	Facets  map[string][]string
+	Stored  []byte
+}
+
+// Save writes the index to path. The file is replaced at once, a crash while
+// saving leaves the previous snapshot in place. The documents are copied under
+// the read lock and written without it, so searches and writes do not wait
+// for the disk.
+func (i *Index) Save(path string) error {
+	i.saving.Lock()
+	defer i.saving.Unlock()
+
+	// the documents are never modified once indexed, copying the pointers is enough
+	i.mu.RLock()
+	snap := snapshot{
+		Version:   snapshotVersion,
+		Languages: i.analyzer.names(),
+		Docs:      make(map[int]snapshotDocument, len(i.docs)),
+	}
+	for id, d := range i.docs {
+		snap.Docs[id] = snapshotDocument{Version: d.version, Terms: d.terms, Facets: d.facets, Stored: d.stored}
+	}
+	generation := i.generation
+	i.mu.RUnlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(snap); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	if i.generation == generation {
+		i.dirty = false
+	}
+	i.mu.Unlock()
+	return nil
+}
+
+// Load replaces the content of the index with the snapshot at path. It returns
+// an error satisfying errors.Is(err, os.ErrNotExist) when there is none yet.
+func (i *Index) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return err
+	}
+	if snap.Version != snapshotVersion || !equal(snap.Languages, i.analyzer.names()) {
+		return ErrStaleSnapshot
+	}
+
+	loaded := NewIndex(i.analyzer)
+	for id, d := range snap.Docs {
+		loaded.put(id, &document{version: d.Version, terms: d.Terms, facets: d.Facets, stored: d.Stored})
+	}
+
+	i.Replace(loaded)
+	i.mu.Lock()
+	i.dirty = false
+	i.mu.Unlock()
+	return nil
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
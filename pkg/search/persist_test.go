@@ -0,0 +1,59 @@
+package search
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIndexSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search", "index.idx")
+	index := testIndex()
+	index.Put(doc(1, 3, "Go generics in practice", "go"))
+	index.Put(doc(2, 1, "Cooking pasta", "food"))
+	if !index.Dirty() {
+		t.Fatal("index not dirty after Put")
+	}
+
+	if err := index.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if index.Dirty() {
+		t.Error("index dirty after Save")
+	}
+
+	loaded := testIndex()
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Dirty() {
+		t.Error("index dirty after Load")
+	}
+	if got := loaded.Versions(); !reflect.DeepEqual(got, map[int]int{1: 3, 2: 1}) {
+		t.Errorf("Versions() = %v", got)
+	}
+
+	res := loaded.Search(ParseQuery("generic", false), Options{Facets: []string{"tag"}})
+	if len(res.Hits) != 1 || res.Hits[0].ID != 1 || string(res.Hits[0].Stored) != "Go generics in practice" {
+		t.Errorf("hits = %+v", res.Hits)
+	}
+	if want := []FacetCount{{"go", 1}}; !reflect.DeepEqual(res.Facets["tag"], want) {
+		t.Errorf("facets = %v, want %v", res.Facets["tag"], want)
+	}
+}
+
+func TestIndexLoadRejectsOtherSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.idx")
+	if err := testIndex().Load(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() of a missing file error = %v", err)
+	}
+
+	if err := NewIndex(Analyzer{Languages: LookupLanguages("en")}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := testIndex().Load(path); !errors.Is(err, ErrStaleSnapshot) {
+		t.Errorf("Load() with other languages error = %v, want %v", err, ErrStaleSnapshot)
+	}
+}
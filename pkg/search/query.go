@@ -0,0 +1,126 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Term is a word of a query, a prefix term matches every word starting with it.
+type Term struct {
+	Word   string
+	Prefix bool
+}
+
+// Query is a parsed full text query. A document matches when it contains every
+// Must term, none of the Not terms and, without Must terms, any Should term.
+type Query struct {
+	Should []Term
+	Must   []Term
+	Not    []Term
+}
+
+// IsEmpty reports whether the query has no term to look for.
+func (q Query) IsEmpty() bool {
+	return len(q.Should) == 0 && len(q.Must) == 0
+}
+
+// Highlights lists the terms a match is highlighted with.
+func (q Query) Highlights() []Term {
+	return append(append([]Term{}, q.Must...), q.Should...)
+}
+
+// ParseQuery reads text the way MySQL reads a full text query. In natural
+// language mode every word is optional. In boolean mode a leading "+" requires
+// a word, a leading "-" excludes it, a trailing "*" matches it as prefix and
+// the operators apply to every word of a "quoted phrase".
+func ParseQuery(text string, boolean bool) Query {
+	var q Query
+	if !boolean {
+		for _, word := range Tokenize(text) {
+			q.Should = append(q.Should, Term{Word: word})
+		}
+		return q
+	}
+
+	for _, token := range splitBoolean(text) {
+		operator := byte(0)
+		if token[0] == '+' || token[0] == '-' {
+			operator = token[0]
+			token = token[1:]
+		}
+		prefix := strings.HasSuffix(token, "*")
+
+		words := Tokenize(token)
+		for k, word := range words {
+			term := Term{Word: word, Prefix: prefix && k == len(words)-1}
+			switch operator {
+			case '+':
+				q.Must = append(q.Must, term)
+			case '-':
+				q.Not = append(q.Not, term)
+			default:
+				q.Should = append(q.Should, term)
+			}
+		}
+	}
+	return q
+}
+
+// splitBoolean splits a boolean query on spaces outside of quotes, the quotes
+// are dropped but the operator in front of them is kept.
+func splitBoolean(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// Tokenize splits text into lower cased words of letters and digits.
+func Tokenize(text string) []string {
+	var words []string
+	for _, v := range scan(text) {
+		words = append(words, v.word)
+	}
+	return words
+}
+
+type token struct {
+	word       string
+	start, end int
+}
+
+// scan finds the words of text together with their byte offsets.
+func scan(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
@@ -0,0 +1,30 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		boolean bool
+		want    Query
+	}{
+		{"natural", "+Go -java", false, Query{Should: []Term{{Word: "go"}, {Word: "java"}}}},
+		{"required and excluded", "+go -java", true, Query{Must: []Term{{Word: "go"}}, Not: []Term{{Word: "java"}}}},
+		{"prefix", "generic*", true, Query{Should: []Term{{Word: "generic", Prefix: true}}}},
+		{"phrase", `+"type param"`, true, Query{Must: []Term{{Word: "type"}, {Word: "param"}}}},
+		{"prefix phrase", `-"old api"*`, true, Query{Not: []Term{{Word: "old"}, {Word: "api", Prefix: true}}}},
+		{"punctuation", "go, generics!", true, Query{Should: []Term{{Word: "go"}, {Word: "generics"}}}},
+		{"empty", "  ", true, Query{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseQuery(tt.text, tt.boolean); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
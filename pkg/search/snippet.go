@@ -0,0 +1,73 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+	ellipsis       = "…"
+)
+
+// Snippet cuts about size characters of text around the first word matching
+// match, see Analyzer.Matcher. The text is HTML escaped and the matching words
+// are wrapped in <mark></mark>, without a match the snippet is the start of
+// the text.
+func Snippet(text string, match func(word string) bool, size int) string {
+	tokens := scan(text)
+
+	var matches []token
+	for _, v := range tokens {
+		if match(v.word) {
+			matches = append(matches, v)
+		}
+	}
+
+	// start a quarter of the snippet before the first match, on a word boundary
+	start := 0
+	if len(matches) > 0 {
+		start = matches[0].start
+		for k := len(tokens) - 1; k >= 0; k-- {
+			if tokens[k].start < matches[0].start && utf8.RuneCountInString(text[tokens[k].start:matches[0].start]) <= size/4 {
+				start = tokens[k].start
+			}
+		}
+	}
+
+	end := len(text)
+	if utf8.RuneCountInString(text[start:]) > size {
+		end = start
+		for k := 0; k < size && end < len(text); k++ {
+			_, width := utf8.DecodeRuneInString(text[end:])
+			end += width
+		}
+		// do not cut the last word in half
+		for _, v := range tokens {
+			if v.start > start && v.start < end && v.end > end {
+				end = v.start
+			}
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	cursor := start
+	for _, v := range matches {
+		if v.start < start || v.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[cursor:v.start]))
+		b.WriteString(highlightOpen + html.EscapeString(text[v.start:v.end]) + highlightClose)
+		cursor = v.end
+	}
+	b.WriteString(html.EscapeString(strings.TrimRight(text[cursor:end], " \t\r\n")))
+	if end < len(text) {
+		b.WriteString(ellipsis)
+	}
+	return strings.TrimSpace(b.String())
+}
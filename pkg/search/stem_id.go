@@ -0,0 +1,99 @@
+package search
+
+import "strings"
+
+// stemIndonesian removes the affixes of an Indonesian word following the
+// Tala stemmer, "pembelajaran" becomes "ajar" and "menuliskannya" "tulis".
+// An affix is only removed when at least two syllables, counted as vowels
+// the way Tala does, are left.
+func stemIndonesian(word string) string {
+	word = trimSuffix(word, "kah", "lah", "tah", "pun")
+	word = trimSuffix(word, "ku", "mu", "nya")
+
+	if stem, ok := trimFirstPrefix(word); ok {
+		word = stem
+		if stem, ok := trimDerivation(word); ok {
+			word, _ = trimSecondPrefix(stem)
+		}
+		return word
+	}
+
+	word, _ = trimSecondPrefix(word)
+	word, _ = trimDerivation(word)
+	return word
+}
+
+// trimFirstPrefix removes meN-, peN-, di-, ter- or ke-. The nasal of meN- and
+// peN- replaces the first letter of the root, menulis is me + tulis.
+func trimFirstPrefix(word string) (string, bool) {
+	rules := []struct {
+		prefix, restore string
+	}{
+		{"meng", ""}, {"meny", "s"}, {"men", "t"}, {"mem", "p"}, {"me", ""},
+		{"peng", ""}, {"peny", "s"}, {"pen", "t"}, {"pem", "p"},
+		{"di", ""}, {"ter", ""}, {"ke", ""},
+	}
+	for _, rule := range rules {
+		if !strings.HasPrefix(word, rule.prefix) {
+			continue
+		}
+		rest := word[len(rule.prefix):]
+		// the root keeps its consonant when the nasal is followed by one, membaca is mem + baca
+		if rule.restore != "" && (rule.restore == "s" || startsWithVowel(rest)) {
+			rest = rule.restore + rest
+		}
+		if syllables(rest) >= 2 {
+			return rest, true
+		}
+	}
+	return word, false
+}
+
+// trimSecondPrefix removes ber-, per- or pe-.
+func trimSecondPrefix(word string) (string, bool) {
+	for _, prefix := range []string{"ber", "bel", "be", "per", "pel", "pe"} {
+		if strings.HasPrefix(word, prefix) && syllables(word[len(prefix):]) >= 2 {
+			return word[len(prefix):], true
+		}
+	}
+	return word, false
+}
+
+// trimDerivation removes the derivational suffixes -kan, -an and -i.
+func trimDerivation(word string) (string, bool) {
+	stem := trimSuffix(word, "kan", "an", "i")
+	return stem, stem != word
+}
+
+// trimSuffix removes the first of suffixes word ends with.
+func trimSuffix(word string, suffixes ...string) string {
+	for _, suffix := range suffixes {
+		if stem := strings.TrimSuffix(word, suffix); stem != word && syllables(stem) >= 2 {
+			return stem
+		}
+	}
+	return word
+}
+
+func startsWithVowel(word string) bool {
+	return word != "" && isVowel(word[0])
+}
+
+// syllables counts the vowels of word.
+func syllables(word string) int {
+	n := 0
+	for i := 0; i < len(word); i++ {
+		if isVowel(word[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package search
+
+// englishStopwords are the most frequent English words, they say nothing of
+// what a text is about.
+var englishStopwords = wordSet(`
+a about above after again against all am an and any are as at be because
+been before being below between both but by can could did do does doing down
+during each few for from further had has have having he her here hers herself
+him himself his how i if in into is it its itself just me more most my myself
+no nor not now of off on once only or other our ours ourselves out over own
+same she should so some such than that the their theirs them themselves then
+there these they this those through to too under until up very was we were
+what when where which while who whom why will with would you your yours
+yourself yourselves
+`)
+
+// indonesianStopwords are the most frequent Indonesian words.
+var indonesianStopwords = wordSet(`
+ada adalah agar akan aku anda apa apakah atas atau bagaimana bagi bahkan
+bahwa banyak beberapa begitu belum bila bisa boleh bukan dalam dan dapat dari
+daripada demikian dengan di dia dirinya hanya harus hingga ia ini itu jadi
+jika juga kalau kami kamu karena ke kemudian kepada ketika kita lagi lain
+lalu maka masih mereka meski mungkin namun oleh pada para saat saja sama
+sampai sangat saya sebagai sebelum sedang sehingga sejak selain seperti
+serta setelah sudah supaya tanpa tapi telah tentang tersebut tetapi untuk
+walau yaitu yakni yang
+`)
+
+func wordSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range Tokenize(text) {
+		set[word] = true
+	}
+	return set
+}
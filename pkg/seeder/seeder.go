@@ -2,13 +2,20 @@ package seeder
 
 import (
 	"article-app/internal/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 func Seeds(db *gorm.DB) {
+	var admin domain.Role
+	db.Where("name = ?", domain.RoleAdmin).First(&admin)
+
+	verifiedAt := time.Now()
 	db.Create(&domain.User{
-		Email:    "admin@mail.com",
-		Password: "Password123",
+		Email:           "admin@mail.com",
+		Password:        "Password123",
+		Roles:           []domain.Role{admin},
+		EmailVerifiedAt: &verifiedAt,
 	})
 }
@@ -0,0 +1,80 @@
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxLength bounds the generated slug, suffixes added by WithSuffix come on top.
+const MaxLength = 200
+
+// transliterations maps the accented and special latin letters found in
+// Indonesian and European loanwords to plain ascii.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ĝ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ř': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ș': "s",
+	'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+	'þ': "th",
+	'&': " dan ",
+}
+
+// Make turns s into a lowercase, hyphen separated url slug.
+//
+//	slug.Make("Résumé Kegiatan & Rapat 2022") // "resume-kegiatan-dan-rapat-2022"
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	write := func(part string) {
+		for _, r := range part {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				b.WriteRune(r)
+				hyphen = false
+			} else if !hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+				hyphen = true
+			}
+		}
+	}
+
+	for _, r := range strings.ToLower(s) {
+		if t, ok := transliterations[r]; ok {
+			write(t)
+			continue
+		}
+		if r > unicode.MaxASCII {
+			write(" ")
+			continue
+		}
+		write(string(r))
+	}
+
+	res := strings.Trim(b.String(), "-")
+	if len(res) > MaxLength {
+		res = strings.TrimRight(res[:MaxLength], "-")
+	}
+	return res
+}
+
+// WithSuffix returns the n-th candidate for base, n < 2 returns base itself.
+func WithSuffix(base string, n int) string {
+	if n < 2 {
+		return base
+	}
+	return base + "-" + strconv.Itoa(n)
+}
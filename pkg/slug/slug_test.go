@@ -0,0 +1,58 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"Hello World", "hello-world"},
+		{"Résumé Kegiatan & Rapat 2022", "resume-kegiatan-dan-rapat-2022"},
+		{"Straße, Œuvre, Þorn, Æther", "strasse-oeuvre-thorn-aether"},
+		{"Łódź ŞEHİR", "lodz-sehir"},
+		{"  --Go   1.18!!  ", "go-1-18"},
+		{"日本語 title 日本", "title"},
+		{"日本語", ""},
+		{"", ""},
+	} {
+		if got := Make(tt.in); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMakeTruncates(t *testing.T) {
+	if got := Make(strings.Repeat("a", MaxLength+50)); got != strings.Repeat("a", MaxLength) {
+		t.Errorf("Make() length = %d, want %d", len(got), MaxLength)
+	}
+
+	// the cut falls on a separator, it is not kept at the end
+	in := strings.Repeat("a", MaxLength-1) + " bcd"
+	if got := Make(in); got != strings.Repeat("a", MaxLength-1) {
+		t.Errorf("Make() = %q, want no trailing hyphen", got[len(got)-5:])
+	}
+
+	// transliterations expand, the limit applies to the result
+	if got := Make(strings.Repeat("ß", MaxLength)); len(got) != MaxLength {
+		t.Errorf("Make() length = %d, want %d", len(got), MaxLength)
+	}
+}
+
+func TestWithSuffix(t *testing.T) {
+	for _, tt := range []struct {
+		n    int
+		want string
+	}{
+		{0, "go-generics"},
+		{1, "go-generics"},
+		{2, "go-generics-2"},
+		{12, "go-generics-12"},
+	} {
+		if got := WithSuffix("go-generics", tt.n); got != tt.want {
+			t.Errorf("WithSuffix(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
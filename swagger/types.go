@@ -1,5 +1,7 @@
 package swagger
 
+import "article-app/pkg/response"
+
 type BaseResponse struct {
 	Code      string      `json:"code" example:"OK"`
 	Message   string      `json:"message" example:"operasi berhasil dieksekusi."`
@@ -9,6 +11,16 @@ type BaseResponse struct {
 	Timestamp string      `json:"timestamp" example:"2022-04-27 23:19:56"`
 }
 
+type ListResponse struct {
+	Code       string              `json:"code" example:"OK"`
+	Message    string              `json:"message" example:"operasi berhasil dieksekusi."`
+	Data       interface{}         `json:"data"`
+	Pagination response.Pagination `json:"pagination"`
+	Errors     interface{}         `json:"errors"`
+	RequestId  string              `json:"request_id" example:"24fa3770-628c-49de-aa17-3a338f73d99b"`
+	Timestamp  string              `json:"timestamp" example:"2022-04-27 23:19:56"`
+}
+
 type BadRequestResponse struct {
 	Code      string      `json:"code" example:"LBR-02-011"`
 	Message   string      `json:"message" example:"data yang anda minta tidak ditemukan."`